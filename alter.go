@@ -0,0 +1,485 @@
+package godbf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SchemaChange describes a change to the fields of a table, to be made by AlterSchema().
+type SchemaChange func(a *schemaAlteration) error
+
+// ConversionFailure describes a value that could not be converted to the type or length of its field, such as by
+// AlterSchema(), or between a field and a struct field by Unmarshal() and Marshal().
+type ConversionFailure struct {
+	Row   int    // row of the value
+	Field string // name of the field, as it is after the alteration
+	Value string // the value being converted, in its text form
+	Err   error  // why the value could not be converted
+}
+
+func (f ConversionFailure) Error() string {
+	return fmt.Sprintf("row %d, field \"%s\": %v", f.Row, f.Field, f.Err)
+}
+
+// Unwrap returns the reason the value could not be converted.
+func (f ConversionFailure) Unwrap() error {
+	return f.Err
+}
+
+// schemaAlteration is the layout of the fields of a table, as it is being changed.
+type schemaAlteration struct {
+	columns       []alteredColumn
+	blankFailures bool
+}
+
+// alteredColumn describes a field of the altered table, and the field of the original table it takes its values
+// from, if any.
+type alteredColumn struct {
+	name          string
+	fieldType     DbaseDataType
+	length        byte
+	decimalPlaces uint8
+	source        int // index of the original field, or -1 for an added field
+}
+
+// AddField adds a field of the type given after all others. The length given is ignored for types of a fixed length.
+// Values of the field are left blank.
+func AddField(fieldName string, fieldType DbaseDataType, length byte, decimalPlaces uint8) SchemaChange {
+	return func(a *schemaAlteration) error {
+		if _, found := a.column(fieldName); found {
+			return fmt.Errorf("Field name \"%s\" already exists", fieldName)
+		}
+		a.columns = append(a.columns, alteredColumn{
+			name:          fieldName,
+			fieldType:     fieldType,
+			length:        length,
+			decimalPlaces: decimalPlaces,
+			source:        -1,
+		})
+		return nil
+	}
+}
+
+// DropField removes the field of the given name, along with its values.
+func DropField(fieldName string) SchemaChange {
+	return func(a *schemaAlteration) error {
+		i, found := a.column(fieldName)
+		if !found {
+			return fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+		}
+		a.columns = append(a.columns[:i], a.columns[i+1:]...)
+		return nil
+	}
+}
+
+// RenameField renames the field of the given name, keeping its values.
+func RenameField(fieldName string, newFieldName string) SchemaChange {
+	return func(a *schemaAlteration) error {
+		i, found := a.column(fieldName)
+		if !found {
+			return fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+		}
+		if _, found = a.column(newFieldName); found && newFieldName != fieldName {
+			return fmt.Errorf("Field name \"%s\" already exists", newFieldName)
+		}
+		a.columns[i].name = newFieldName
+		return nil
+	}
+}
+
+// ResizeField changes the length and decimal places of a Character, Numeric or Float field of the given name,
+// converting its values to match. Numeric and Float values are rounded to the new decimal places.
+func ResizeField(fieldName string, length byte, decimalPlaces uint8) SchemaChange {
+	return func(a *schemaAlteration) error {
+		i, found := a.column(fieldName)
+		if !found {
+			return fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+		}
+		if !a.columns[i].fieldType.oneOf(Character, Numeric, Float) {
+			return fmt.Errorf("length of field \"%s\" of type '%c' cannot be changed", fieldName, a.columns[i].fieldType)
+		}
+		a.columns[i].length = length
+		a.columns[i].decimalPlaces = decimalPlaces
+		return nil
+	}
+}
+
+// ChangeFieldType changes the type of the field of the given name, converting its values to match. The length given
+// is ignored for types of a fixed length. Any value can be converted to text (Character or Memo), numbers between
+// the numeric types and from text, logical values from text, and dates between the date types and from text, as
+// per SetFieldValue(). Values that cannot be converted are reported by AlterSchema().
+func ChangeFieldType(fieldName string, fieldType DbaseDataType, length byte, decimalPlaces uint8) SchemaChange {
+	return func(a *schemaAlteration) error {
+		i, found := a.column(fieldName)
+		if !found {
+			return fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+		}
+		if !convertible(a.columns[i].fieldType, fieldType) {
+			return fmt.Errorf("type of field \"%s\" cannot be changed from '%c' to '%c'",
+				fieldName, a.columns[i].fieldType, fieldType)
+		}
+		a.columns[i].fieldType = fieldType
+		a.columns[i].length = length
+		a.columns[i].decimalPlaces = decimalPlaces
+		return nil
+	}
+}
+
+// MoveField moves the field of the given name to the position given, counting from 0, keeping its values.
+func MoveField(fieldName string, position int) SchemaChange {
+	return func(a *schemaAlteration) error {
+		i, found := a.column(fieldName)
+		if !found {
+			return fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+		}
+		if position < 0 || position >= len(a.columns) {
+			return fmt.Errorf("position %d of field \"%s\" is out of range", position, fieldName)
+		}
+		column := a.columns[i]
+		a.columns = append(a.columns[:i], a.columns[i+1:]...)
+		a.columns = append(a.columns[:position], append([]alteredColumn{column}, a.columns[position:]...)...)
+		return nil
+	}
+}
+
+// BlankFailedConversions has AlterSchema() leave values that cannot be converted blank, rather than fail.
+func BlankFailedConversions() SchemaChange {
+	return func(a *schemaAlteration) error {
+		a.blankFailures = true
+		return nil
+	}
+}
+
+// column returns the index of the column of the given name.
+func (a *schemaAlteration) column(fieldName string) (int, bool) {
+	for i := range a.columns {
+		if a.columns[i].name == fieldName {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// AlterSchema makes the changes given to the fields of the table, in order, even once the table has been loaded or
+// records have been added. Every record is rewritten into the new layout, keeping its deletion flag, and values are
+// converted where the type or length of their field changes. The memo file, if any, is rebuilt to hold only the memos
+// of the remaining memo fields. Index files are not maintained, and need to be rebuilt.
+//
+// Values that cannot be converted are returned. Unless configured otherwise with BlankFailedConversions(), an error
+// is returned when there are any, and the table is left unchanged.
+func (dt *DbfTable) AlterSchema(changes ...SchemaChange) (failures []ConversionFailure, err error) {
+	a := &schemaAlteration{}
+	for i := range dt.fields {
+		fd := &dt.fields[i]
+		if fd.fieldType == NullFlags && fd.IsSystem() {
+			continue // rebuilt to match the nullable fields of the altered table
+		}
+		a.columns = append(a.columns, alteredColumn{
+			name:          fd.name,
+			fieldType:     fd.fieldType,
+			length:        fd.length,
+			decimalPlaces: fd.decimalPlaces,
+			source:        i,
+		})
+	}
+
+	for _, change := range changes {
+		if err = change(a); err != nil {
+			return
+		}
+	}
+	if len(a.columns) == 0 {
+		return nil, errors.New("a table needs at least one field")
+	}
+
+	var table *DbfTable
+	if table, err = dt.alteredTable(a.columns); err != nil {
+		return
+	}
+	if failures, err = dt.copyRecords(table, a.columns); err != nil {
+		return
+	}
+
+	if len(failures) > 0 && !a.blankFailures {
+		first := failures[0]
+		err = fmt.Errorf("%d values cannot be converted, such as \"%s\" of field \"%s\": %v",
+			len(failures), first.Value, first.Field, first.Err)
+		return
+	}
+
+	table.hasEndOfFileMarker = dt.hasEndOfFileMarker
+	table.createdFromScratch = dt.createdFromScratch
+	table.schemaLocked = dt.schemaLocked
+	*dt = *table
+	return
+}
+
+// alteredTable creates an empty table of the same format and encoding, with the fields of the columns given. The
+// header of the table is kept, except for the fields, and for the flag of a production index.
+func (dt *DbfTable) alteredTable(columns []alteredColumn) (table *DbfTable, err error) {
+	table = New(dt.Encoding(), WithFormat(dt.format), WithLocation(dt.Location()))
+	layout := dt.format.fieldDescriptorLayout()
+
+	for _, column := range columns {
+		length := dt.format.fieldLength(column.fieldType, column.length)
+
+		if column.source >= 0 && dt.fields[column.source].raw && column.fieldType == dt.fields[column.source].fieldType {
+			err = table.addRawField(column.name, column.fieldType, length, column.decimalPlaces)
+		} else {
+			err = table.addField(column.name, column.fieldType, length, column.decimalPlaces)
+		}
+		if err != nil {
+			return
+		}
+		if column.source < 0 {
+			continue
+		}
+
+		source := &dt.fields[column.source]
+		fd := &table.fields[len(table.fields)-1]
+		if fd.fieldType == source.fieldType && fd.length == source.length {
+			// carry the rest of the descriptor over, such as the flags and the next value of autoincrement fields
+			copy(fd.fieldStore[layout.nameLength:], source.fieldStore[layout.nameLength:])
+			fd.fieldStore[layout.decimalIndex] = fd.decimalPlaces
+			fd.flags = source.flags
+		} else if fd.fieldType.oneOf(Character, Memo) {
+			fd.flags = source.flags & (nullableFieldFlag | binaryFieldFlag)
+		} else {
+			fd.flags = source.flags & nullableFieldFlag
+		}
+		if dt.format == VisualFoxPro {
+			fd.fieldStore[fieldFlagsIndex] = fd.flags
+		}
+	}
+
+	if dt.format == VisualFoxPro {
+		if err = table.resizeNullFlagsField(); err != nil {
+			return
+		}
+	}
+	table.updateHeader()
+
+	hasMemo := table.hasMemoFields()
+	copy(table.dataStore[12:table.format.headerPrefixLength()], dt.dataStore[12:dt.format.headerPrefixLength()])
+	table.dataStore[tableFlagsIndex] &^= productionMDXFlag
+	if dt.format == VisualFoxPro {
+		if hasMemo {
+			table.dataStore[tableFlagsIndex] |= vfpTableHasMemoFlag
+		} else {
+			table.dataStore[tableFlagsIndex] &^= vfpTableHasMemoFlag
+		}
+	}
+	copy(table.backlinkArea(), dt.backlinkArea())
+
+	if hasMemo == dt.hasMemoFields() {
+		table.fileSignature = dt.fileSignature
+	} else {
+		table.fileSignature = dt.format.signature(hasMemo)
+	}
+	table.dataStore[0] = table.fileSignature
+
+	switch {
+	case !hasMemo:
+		table.memo = nil
+	case dt.memo != nil:
+		table.memo = dt.memo.empty()
+	}
+	return
+}
+
+// copyRecords adds the records of the table to the altered table given, converting values as the columns require.
+func (dt *DbfTable) copyRecords(table *DbfTable, columns []alteredColumn) (failures []ConversionFailure, err error) {
+	autoIncrementAdded := false
+	for _, column := range columns {
+		autoIncrementAdded = autoIncrementAdded || column.source < 0 && column.fieldType == AutoIncrement
+	}
+
+	for row := 0; row < dt.NumberOfRecords(); row++ {
+		record := dt.record(row)
+		table.dataStore = append(table.dataStore, table.emptyRecord()...)
+		table.setNumberOfRecords(uint32(row + 1))
+
+		altered := table.record(row)
+		altered[recordDeletionFlagIndex] = record[recordDeletionFlagIndex]
+		if autoIncrementAdded {
+			table.assignAutoIncrementValues(altered)
+		}
+
+		for i, column := range columns {
+			if column.source < 0 {
+				continue
+			}
+
+			var failure error
+			if failure, err = dt.copyValue(table, row, i, column.source); err != nil {
+				return
+			}
+			if failure != nil {
+				value, _ := dt.sourceValue(record, column.source)
+				failures = append(failures, ConversionFailure{
+					Row:   row,
+					Field: table.fields[i].name,
+					Value: value,
+					Err:   failure,
+				})
+				table.clearValue(altered, i)
+			}
+		}
+	}
+
+	if autoIncrementAdded {
+		// the values of existing autoincrement fields are carried over, and so is the next value in their sequence
+		dt.restoreAutoIncrementNextValues(table, columns)
+	}
+	return
+}
+
+// copyValue copies the value of the original field with the given source index into the field of the altered table
+// with the given index, for the record at the given row. A failure to convert the value is returned separately from
+// an error reading it.
+func (dt *DbfTable) copyValue(table *DbfTable, row int, fieldIndex int, source int) (failure error, err error) {
+	record := dt.record(row)
+	altered := table.record(row)
+	fd := &table.fields[fieldIndex]
+
+	if dt.fieldIsNullInRecord(record, source) && table.SetFieldNull(row, fieldIndex) == nil {
+		return
+	}
+
+	if fd.fieldType == dt.fields[source].fieldType && fd.length == dt.fields[source].length &&
+		fd.decimalPlaces == dt.fields[source].decimalPlaces {
+		if !fd.fieldType.usesMemo() {
+			offset := dt.fieldOffset(source)
+			copy(altered[table.fieldOffset(fieldIndex):], record[offset:offset+int(fd.length)])
+			return
+		}
+
+		var value []byte
+		if value, err = dt.memoBytesFromRecord(record, source); err != nil {
+			return
+		}
+		err = table.setMemoBytesInRecord(altered, fieldIndex, value, fd.holdsBinaryData())
+		return
+	}
+
+	var value string
+	if value, err = dt.sourceValue(record, source); err != nil {
+		return
+	}
+	failure = table.convertValue(row, fieldIndex, value)
+	return
+}
+
+// sourceValue returns the value of the field with the given index from the bytes of a single record, as per
+// FieldValue(), but with an error if a memo cannot be read.
+func (dt *DbfTable) sourceValue(record []byte, fieldIndex int) (string, error) {
+	if dt.fields[fieldIndex].fieldType.usesMemo() {
+		return dt.memoValueFromRecord(record, fieldIndex)
+	}
+	return dt.fieldValueFromRecord(record, fieldIndex), nil
+}
+
+// convertValue sets the field with the given index, for the record at the given row, to a value taken from a field of
+// another type or length, validating that it can be represented without loss. A blank value blanks the field.
+func (dt *DbfTable) convertValue(row int, fieldIndex int, value string) error {
+	fd := &dt.fields[fieldIndex]
+	if value == "" {
+		return dt.SetFieldValue(row, fieldIndex, "")
+	}
+
+	switch fd.fieldType {
+	case Character:
+		encoded, err := dt.encodeString(value)
+		if err != nil {
+			return err
+		}
+		if len(encoded) > int(fd.length) {
+			return fmt.Errorf("value does not fit field of length %d", fd.length)
+		}
+	case Numeric, Float:
+		r, err := parseDecimal(value)
+		if err != nil {
+			return err
+		}
+		return dt.setNumericFieldValue(row, fieldIndex, r, nil)
+	case Integer:
+		r, err := parseDecimal(value)
+		if err != nil {
+			return err
+		}
+		if !r.IsInt() {
+			return fmt.Errorf("value %s is not a whole number", value)
+		}
+		value = r.Num().String()
+	case Logical:
+		switch strings.ToUpper(strings.Trim(value, ".")) {
+		case "T", "Y", "TRUE", "YES":
+			value = "T"
+		case "F", "N", "FALSE", "NO":
+			value = "F"
+		case "?":
+			return nil
+		default:
+			return fmt.Errorf("invalid logical value \"%s\"", value)
+		}
+	case Date:
+		layout := dateLayout
+		if len(value) == len(DateTimeLayout) {
+			layout = DateTimeLayout
+		}
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			return fmt.Errorf("invalid date \"%s\"", value)
+		}
+		value = t.Format(dateLayout)
+	}
+	return dt.SetFieldValue(row, fieldIndex, value)
+}
+
+// clearValue leaves the field with the given index blank in the bytes of a single record, as in a new record.
+func (dt *DbfTable) clearValue(record []byte, fieldIndex int) {
+	offset := dt.fieldOffset(fieldIndex)
+	clearBytes(record[offset : offset+int(dt.fields[fieldIndex].length)])
+}
+
+// restoreAutoIncrementNextValues sets the next value of each autoincrement field of the altered table given that was
+// carried over from the table, to the next value it had in the table.
+func (dt *DbfTable) restoreAutoIncrementNextValues(table *DbfTable, columns []alteredColumn) {
+	if dt.format != DBase7 {
+		return
+	}
+
+	layout := dt.format.fieldDescriptorLayout()
+	for i, column := range columns {
+		if column.source < 0 || table.fields[i].fieldType != AutoIncrement ||
+			dt.fields[column.source].fieldType != AutoIncrement {
+			continue
+		}
+
+		nextValue := dt.fields[column.source].fieldStore[dBase7AutoIncrementNextValueIndex : dBase7AutoIncrementNextValueIndex+4]
+		copy(table.fields[i].fieldStore[dBase7AutoIncrementNextValueIndex:], nextValue)
+		descriptor := table.dataStore[table.format.headerPrefixLength()+i*layout.descriptorLength:]
+		copy(descriptor[dBase7AutoIncrementNextValueIndex:], nextValue)
+	}
+}
+
+// convertible returns true if values of the first type can be converted to values of the second.
+func convertible(from DbaseDataType, to DbaseDataType) bool {
+	switch {
+	case from == to:
+		return true
+	case from.isBinaryMemo() || to.isBinaryMemo() || to == AutoIncrement:
+		return false
+	case to.oneOf(Character, Memo):
+		return true
+	case to.oneOf(Numeric, Float, Integer, Currency, Double, Double7):
+		return from.oneOf(Character, Memo, Numeric, Float, Integer, Currency, Double, Double7, AutoIncrement)
+	case to == Logical:
+		return from.oneOf(Character, Memo)
+	case to.oneOf(Date, DateTime, Timestamp):
+		return from.oneOf(Character, Memo, Date, DateTime, Timestamp)
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package godbf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func createAlterTable(t *testing.T, format Format) *DbfTable {
+	table := New(charmap.CodePage866, WithFormat(format))
+	require.Nil(t, table.AddTextField("NAME", 10))
+	require.Nil(t, table.AddNumberField("AMOUNT", 8, 2))
+	require.Nil(t, table.AddTextField("CODE", 5))
+	require.Nil(t, table.AddMemoField("NOTES"))
+
+	rows := [][]string{
+		{"Иван", "12.50", "42", "first memo"},
+		{"Мария", "-3.25", "007", ""},
+		{"Пётр", "", "", "third memo"},
+	}
+	for _, values := range rows {
+		row, err := table.AddNewRecord()
+		require.Nil(t, err)
+		for i, value := range values {
+			require.Nil(t, table.SetFieldValue(row, i, value))
+		}
+	}
+	require.Nil(t, table.DeleteRecord(1))
+	return table
+}
+
+func TestDbfTable_AlterSchema_LoadedTable(t *testing.T) {
+	tempFilename := filepath.Join("testdata", "tempAlterTable.dbf")
+	require.Nil(t, createAlterTable(t, DBaseIV).Save(tempFilename, os.ModePerm))
+	defer os.Remove(tempFilename)
+	defer os.Remove(filepath.Join("testdata", "tempAlterTable.dbt"))
+
+	table, err := NewFromFile(tempFilename, charmap.CodePage866)
+	require.Nil(t, err)
+	require.NotNil(t, table.AddTextField("CITY", 10))
+
+	failures, err := table.AlterSchema(
+		AddField("CITY", Character, 10, 0),
+		AddField("PAID", Logical, 0, 0),
+		RenameField("NAME", "FULLNAME"),
+		ResizeField("FULLNAME", 20, 0),
+		ChangeFieldType("CODE", Numeric, 4, 0),
+		ChangeFieldType("AMOUNT", Character, 6, 0),
+		MoveField("NOTES", 0),
+	)
+	require.Nil(t, err)
+	require.Empty(t, failures)
+
+	require.Equal(t, table.FieldNames(), []string{"NOTES", "FULLNAME", "AMOUNT", "CODE", "CITY", "PAID"})
+	require.EqualValues(t, table.Fields()[1].Length(), 20)
+	require.Equal(t, table.Fields()[3].FieldType(), Numeric)
+	require.Equal(t, table.NumberOfRecords(), 3)
+	require.True(t, table.RowIsDeleted(1))
+	require.EqualValues(t, table.dataStore[0], 0x8B)
+
+	expected := [][]string{
+		{"first memo", "Иван", "12.50", "42", "", ""},
+		{"", "Мария", "-3.25", "7", "", ""},
+		{"third memo", "Пётр", "", "", "", ""},
+	}
+	for row, values := range expected {
+		require.Equal(t, table.GetRowAsSlice(row), values)
+	}
+
+	// the table is saved in its new layout
+	require.Nil(t, table.SetFieldValueByName(2, "CITY", "Москва"))
+	require.Nil(t, table.Save(tempFilename, os.ModePerm))
+	tableUnderTest, err := NewFromFile(tempFilename, charmap.CodePage866)
+	require.Nil(t, err)
+	require.Equal(t, tableUnderTest.FieldNames(), table.FieldNames())
+	require.Equal(t, tableUnderTest.GetRowAsSlice(0), expected[0])
+	require.Equal(t, tableUnderTest.GetRowAsSlice(2), []string{"third memo", "Пётр", "", "", "Москва", ""})
+}
+
+func TestDbfTable_AlterSchema_DropFieldRebuildsMemoFile(t *testing.T) {
+	table := createAlterTable(t, FoxPro)
+
+	_, err := table.AlterSchema(DropField("NOTES"), DropField("CODE"))
+	require.Nil(t, err)
+	require.Equal(t, table.FieldNames(), []string{"NAME", "AMOUNT"})
+	require.Nil(t, table.memo)
+	require.EqualValues(t, table.dataStore[0], 0x03)
+	require.Equal(t, table.GetRowAsSlice(0), []string{"Иван", "12.50"})
+
+	_, err = table.AlterSchema(DropField("NAME"), DropField("AMOUNT"))
+	require.EqualError(t, err, "a table needs at least one field")
+}
+
+func TestDbfTable_AlterSchema_ConversionFailures(t *testing.T) {
+	table := createAlterTable(t, DBaseIII)
+	require.Nil(t, table.SetFieldValueByName(2, "CODE", "X1"))
+	dataStore := append([]byte(nil), table.dataStore...)
+
+	changes := []SchemaChange{ChangeFieldType("CODE", Numeric, 5, 0), ResizeField("AMOUNT", 3, 1)}
+	failures, err := table.AlterSchema(changes...)
+	require.EqualError(t, err,
+		"3 values cannot be converted, such as \"12.50\" of field \"AMOUNT\": value 12.5 does not fit field \"AMOUNT\" of length 3")
+	require.Len(t, failures, 3)
+	require.Equal(t, []int{failures[0].Row, failures[1].Row, failures[2].Row}, []int{0, 1, 2})
+	require.Equal(t, failures[2].Field, "CODE")
+	require.Equal(t, failures[2].Value, "X1")
+	require.EqualError(t, failures[2].Err, "invalid decimal value \"X1\"")
+	require.Equal(t, table.dataStore, dataStore)
+	require.Equal(t, table.FieldNames(), []string{"NAME", "AMOUNT", "CODE", "NOTES"})
+
+	failures, err = table.AlterSchema(append(changes, BlankFailedConversions())...)
+	require.Nil(t, err)
+	require.Len(t, failures, 3)
+	require.Equal(t, table.GetRowAsSlice(0), []string{"Иван", "", "42", "first memo"})
+	require.Equal(t, table.GetRowAsSlice(2), []string{"Пётр", "", "", "third memo"})
+
+	_, err = table.AlterSchema(ChangeFieldType("AMOUNT", Logical, 1, 0))
+	require.EqualError(t, err, "type of field \"AMOUNT\" cannot be changed from 'N' to 'L'")
+	_, err = table.AlterSchema(ResizeField("NOTES", 20, 0))
+	require.EqualError(t, err, "length of field \"NOTES\" of type 'M' cannot be changed")
+	_, err = table.AlterSchema(RenameField("NAME", "CODE"))
+	require.EqualError(t, err, "Field name \"CODE\" already exists")
+	_, err = table.AlterSchema(DropField("MISSING"))
+	require.EqualError(t, err, "Field name \"MISSING\" does not exist")
+}
+
+func TestDbfTable_AlterSchema_TextToLogicalAndDate(t *testing.T) {
+	table := New(nil)
+	require.Nil(t, table.AddTextField("PAID", 5))
+	require.Nil(t, table.AddTextField("BORN", 8))
+	for _, values := range [][]string{{".T.", "19991231"}, {"n", ""}} {
+		row, err := table.AddNewRecord()
+		require.Nil(t, err)
+		require.Nil(t, table.SetFieldValue(row, 0, values[0]))
+		require.Nil(t, table.SetFieldValue(row, 1, values[1]))
+	}
+
+	_, err := table.AlterSchema(ChangeFieldType("PAID", Logical, 0, 0), ChangeFieldType("BORN", Date, 0, 0))
+	require.Nil(t, err)
+	require.Equal(t, table.GetRowAsSlice(0), []string{"T", "19991231"})
+	require.Equal(t, table.GetRowAsSlice(1), []string{"F", ""})
+	require.True(t, table.FieldIsNull(1, 1))
+}
+
+func TestDbfTable_AlterSchema_KeepsNullableFields(t *testing.T) {
+	table := New(nil, WithFormat(VisualFoxPro))
+	require.Nil(t, table.AddTextField("NAME", 10))
+	require.Nil(t, table.AddNumberField("AMOUNT", 6, 0))
+	require.Nil(t, table.SetFieldNullable("AMOUNT"))
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Nil(t, table.SetFieldValueByName(row, "NAME", "name"))
+	require.Nil(t, table.SetFieldNullByName(row, "AMOUNT"))
+
+	_, err = table.AlterSchema(MoveField("AMOUNT", 0), AddField("CODE", Integer, 0, 0))
+	require.Nil(t, err)
+	require.Equal(t, table.FieldNames(), []string{"AMOUNT", "NAME", "CODE"})
+	require.Len(t, table.AllFields(), 4)
+	require.True(t, table.Fields()[0].IsNullable())
+	require.True(t, table.FieldIsNull(0, 0))
+	require.Equal(t, table.FieldValue(0, 1), "name")
+}
+
+func TestDbfTable_AlterSchema_KeepsAutoIncrementSequence(t *testing.T) {
+	table := New(nil, WithFormat(DBase7))
+	require.Nil(t, table.AddAutoIncrementField("ID"))
+	require.Nil(t, table.AddTextField("NAME", 10))
+	for i := 0; i < 2; i++ {
+		_, err := table.AddNewRecord()
+		require.Nil(t, err)
+	}
+
+	_, err := table.AlterSchema(AddField("SEQ", AutoIncrement, 0, 0), DropField("NAME"))
+	require.Nil(t, err)
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Equal(t, table.GetRowAsSlice(0), []string{"1", "1"})
+	require.Equal(t, table.GetRowAsSlice(row), []string{"3", "3"})
+}
@@ -0,0 +1,342 @@
+package godbf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DateTimeLayout is the layout, as per time.Format(), of the string values of DateTime fields.
+	DateTimeLayout = "20060102150405"
+	dateLayout     = "20060102"
+
+	currencyScale     = 10000
+	currencyDecimals  = 4
+	unixEpochJulian   = 2440588 // Julian day number of 1970-01-01
+	millisecondsInDay = 24 * 60 * 60 * 1000
+
+	dBase7SignBit32 uint32 = 1 << 31
+	dBase7SignBit64 uint64 = 1 << 63
+)
+
+// binaryFieldValue decodes the binary representation of a field of the given type into its canonical string form.
+// Integers are formatted in base 10, currency with its 4 decimal places, doubles with as few digits as are needed to
+// represent the value exactly, and date-times as per DateTimeLayout. Date-times that have not been set, and dBase 7
+// values that have not been set, yield an empty string.
+func (dt *DbfTable) binaryFieldValue(fieldType DbaseDataType, b []byte) string {
+	if dt.format == DBase7 && isZeroBytes(b) {
+		return ""
+	}
+
+	switch fieldType {
+	case Integer, AutoIncrement:
+		return strconv.FormatInt(int64(dt.decodeInt32(b)), 10)
+	case Currency:
+		return formatCurrency(int64(binary.LittleEndian.Uint64(b)))
+	case Double, Double7:
+		return strconv.FormatFloat(dt.decodeFloat64(b), 'f', -1, 64)
+	case DateTime, Timestamp:
+		t, ok := dt.decodeDateTime(b)
+		if !ok {
+			return ""
+		}
+		return t.Format(DateTimeLayout)
+	}
+	return ""
+}
+
+// setBinaryFieldValue parses the string value supplied, encoding it into the binary representation of a field of the
+// given type. An empty value encodes as zero, or as a date-time that has not been set. For dBase 7 any empty value
+// encodes as a value that has not been set.
+func (dt *DbfTable) setBinaryFieldValue(fieldType DbaseDataType, b []byte, value string) (err error) {
+	value = strings.TrimSpace(value)
+	if dt.format == DBase7 && value == "" {
+		clearBytes(b)
+		return
+	}
+
+	switch fieldType {
+	case Integer, AutoIncrement:
+		var i int64
+		if value != "" {
+			if i, err = strconv.ParseInt(value, 10, 32); err != nil {
+				return
+			}
+		}
+		dt.encodeInt32(b, int32(i))
+	case Currency:
+		var units int64
+		if value != "" {
+			if units, err = parseCurrency(value); err != nil {
+				return
+			}
+		}
+		binary.LittleEndian.PutUint64(b, uint64(units))
+	case Double, Double7:
+		var f float64
+		if value != "" {
+			if f, err = strconv.ParseFloat(value, 64); err != nil {
+				return
+			}
+		}
+		dt.encodeFloat64(b, f)
+	case DateTime, Timestamp:
+		if value == "" {
+			clearBytes(b)
+			return
+		}
+
+		layout := DateTimeLayout
+		if len(value) == len(dateLayout) {
+			layout = dateLayout
+		}
+
+		var t time.Time
+		if t, err = time.Parse(layout, value); err != nil {
+			return
+		}
+		dt.encodeDateTime(b, t)
+	}
+	return
+}
+
+// decodeInt32 decodes a 4-byte integer. Visual FoxPro stores these little-endian, while dBase 7 stores them
+// big-endian with the sign bit flipped, so that they sort bytewise.
+func (dt *DbfTable) decodeInt32(b []byte) int32 {
+	if dt.format == DBase7 {
+		return int32(binary.BigEndian.Uint32(b) ^ dBase7SignBit32)
+	}
+	return int32(binary.LittleEndian.Uint32(b))
+}
+
+func (dt *DbfTable) encodeInt32(b []byte, i int32) {
+	if dt.format == DBase7 {
+		binary.BigEndian.PutUint32(b, uint32(i)^dBase7SignBit32)
+		return
+	}
+	binary.LittleEndian.PutUint32(b, uint32(i))
+}
+
+// decodeFloat64 decodes an 8-byte IEEE 754 floating point number. Visual FoxPro stores these little-endian, while
+// dBase 7 stores them big-endian with the sign bit flipped for positive numbers and all bits flipped for negative
+// ones, so that they sort bytewise.
+func (dt *DbfTable) decodeFloat64(b []byte) float64 {
+	if dt.format != DBase7 {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+
+	bits := binary.BigEndian.Uint64(b)
+	if bits&dBase7SignBit64 != 0 {
+		bits ^= dBase7SignBit64
+	} else {
+		bits = ^bits
+	}
+	return math.Float64frombits(bits)
+}
+
+func (dt *DbfTable) encodeFloat64(b []byte, f float64) {
+	bits := math.Float64bits(f)
+	if dt.format != DBase7 {
+		binary.LittleEndian.PutUint64(b, bits)
+		return
+	}
+
+	if bits&dBase7SignBit64 == 0 {
+		bits ^= dBase7SignBit64
+	} else {
+		bits = ^bits
+	}
+	binary.BigEndian.PutUint64(b, bits)
+}
+
+// decodeDateTime decodes a Julian day number and milliseconds since midnight into a time.Time in UTC, ok being false
+// if the date-time has not been set. Both numbers are 4-byte integers, encoded as per decodeInt32.
+func (dt *DbfTable) decodeDateTime(b []byte) (t time.Time, ok bool) {
+	if isZeroBytes(b) {
+		return
+	}
+
+	julianDay := dt.decodeInt32(b[0:4])
+	milliseconds := dt.decodeInt32(b[4:8])
+
+	t = time.Unix(0, 0).UTC().
+		AddDate(0, 0, int(julianDay-unixEpochJulian)).
+		Add(time.Duration(milliseconds) * time.Millisecond)
+	return t, true
+}
+
+// encodeDateTime encodes the wall clock date and time of t as a Julian day number and milliseconds since midnight.
+func (dt *DbfTable) encodeDateTime(b []byte, t time.Time) {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	julianDay := int32(midnight.Unix()/(millisecondsInDay/1000)) + unixEpochJulian
+	milliseconds := int32(((t.Hour()*60+t.Minute())*60+t.Second())*1000 + t.Nanosecond()/int(time.Millisecond))
+
+	dt.encodeInt32(b[0:4], julianDay)
+	dt.encodeInt32(b[4:8], milliseconds)
+}
+
+func isZeroBytes(b []byte) bool {
+	for _, c := range b {
+		if c != null {
+			return false
+		}
+	}
+	return true
+}
+
+// formatCurrency formats currency units (1/10000ths) as a decimal string with 4 decimal places.
+func formatCurrency(units int64) string {
+	sign := ""
+	magnitude := uint64(units)
+	if units < 0 {
+		sign = "-"
+		magnitude = uint64(-units)
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, magnitude/currencyScale, currencyDecimals, magnitude%currencyScale)
+}
+
+// parseCurrency parses a decimal string into currency units (1/10000ths), rounding half away from zero any digits
+// beyond the 4th decimal place.
+func parseCurrency(value string) (units int64, err error) {
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		err = fmt.Errorf("invalid currency value \"%s\"", value)
+		return
+	}
+
+	scaled := roundRatHalfAwayFromZero(r.Mul(r, big.NewRat(currencyScale, 1)))
+	if !scaled.IsInt64() {
+		err = fmt.Errorf("currency value \"%s\" is out of range", value)
+		return
+	}
+	return scaled.Int64(), nil
+}
+
+// roundRatHalfAwayFromZero rounds the rational number to the nearest integer, with halves rounded away from zero.
+func roundRatHalfAwayFromZero(r *big.Rat) *big.Int {
+	doubled := new(big.Int).Mul(r.Num(), big.NewInt(2))
+	doubled.Add(doubled, new(big.Int).Mul(r.Denom(), big.NewInt(int64(r.Sign()))))
+
+	quotient := new(big.Int).Mul(r.Denom(), big.NewInt(2))
+	return quotient.Quo(doubled, quotient)
+}
+
+func clearBytes(b []byte) {
+	for i := range b {
+		b[i] = null
+	}
+}
+
+// binaryField returns the bytes of the field with the given name for the record at the given row, after verifying
+// that the field is of the expected type.
+func (dt *DbfTable) binaryField(row int, fieldName string, fieldTypes ...DbaseDataType) (b []byte, err error) {
+	fieldIndex, found := dt.fieldMap[fieldName]
+	if !found {
+		err = fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+		return
+	}
+
+	if !dt.fields[fieldIndex].fieldType.oneOf(fieldTypes...) {
+		err = fmt.Errorf("type of field \"%s\" is not %s", fieldName, joinDataTypes(fieldTypes))
+		return
+	}
+
+	offset := dt.fieldOffset(fieldIndex)
+	return dt.record(row)[offset : offset+int(dt.fields[fieldIndex].length)], nil
+}
+
+// DateTimeFieldValueByName returns the value of a DateTime or Timestamp field given row number and name provided, in UTC.
+// A date-time that has not been set is returned as the zero time.Time.
+func (dt *DbfTable) DateTimeFieldValueByName(row int, fieldName string) (value time.Time, err error) {
+	var b []byte
+	if b, err = dt.binaryField(row, fieldName, DateTime, Timestamp); err != nil {
+		return
+	}
+	value, _ = dt.decodeDateTime(b)
+	return
+}
+
+// SetDateTimeFieldValueByName sets the value of a DateTime or Timestamp field given row number and name provided.
+// The wall clock date and time of value are stored, as the field does not record a time zone. Milliseconds are
+// retained, while any finer precision is truncated. The zero time.Time clears the field.
+func (dt *DbfTable) SetDateTimeFieldValueByName(row int, fieldName string, value time.Time) (err error) {
+	var b []byte
+	if b, err = dt.binaryField(row, fieldName, DateTime, Timestamp); err != nil {
+		return
+	}
+
+	dt.clearNullFlag(dt.record(row), dt.fieldMap[fieldName])
+	if value.IsZero() {
+		clearBytes(b)
+		return
+	}
+	dt.encodeDateTime(b, value)
+	return
+}
+
+// CurrencyFieldValueByName returns the value of a Currency field given row number and name provided, as an exact
+// number of currency units of 1/10000th each. For example 12.3456 is returned as 123456.
+func (dt *DbfTable) CurrencyFieldValueByName(row int, fieldName string) (units int64, err error) {
+	var b []byte
+	if b, err = dt.binaryField(row, fieldName, Currency); err != nil {
+		return
+	}
+	return int64(binary.LittleEndian.Uint64(b)), nil
+}
+
+// SetCurrencyFieldValueByName sets the value of a Currency field given row number and name provided, as an exact
+// number of currency units of 1/10000th each.
+func (dt *DbfTable) SetCurrencyFieldValueByName(row int, fieldName string, units int64) (err error) {
+	var b []byte
+	if b, err = dt.binaryField(row, fieldName, Currency); err != nil {
+		return
+	}
+	binary.LittleEndian.PutUint64(b, uint64(units))
+	dt.clearNullFlag(dt.record(row), dt.fieldMap[fieldName])
+	return
+}
+
+// Int32FieldValueByName returns the value of an Integer or AutoIncrement field given row number and name provided
+func (dt *DbfTable) Int32FieldValueByName(row int, fieldName string) (value int32, err error) {
+	var b []byte
+	if b, err = dt.binaryField(row, fieldName, Integer, AutoIncrement); err != nil {
+		return
+	}
+	return dt.decodeInt32(b), nil
+}
+
+// DoubleFieldValueByName returns the value of a Double or Double7 field given row number and name provided
+func (dt *DbfTable) DoubleFieldValueByName(row int, fieldName string) (value float64, err error) {
+	var b []byte
+	if b, err = dt.binaryField(row, fieldName, Double, Double7); err != nil {
+		return
+	}
+	return dt.decodeFloat64(b), nil
+}
+
+// SetInt32FieldValueByName sets the value of an Integer or AutoIncrement field given row number and name provided
+func (dt *DbfTable) SetInt32FieldValueByName(row int, fieldName string, value int32) (err error) {
+	var b []byte
+	if b, err = dt.binaryField(row, fieldName, Integer, AutoIncrement); err != nil {
+		return
+	}
+	dt.encodeInt32(b, value)
+	dt.clearNullFlag(dt.record(row), dt.fieldMap[fieldName])
+	return
+}
+
+// SetDoubleFieldValueByName sets the value of a Double or Double7 field given row number and name provided
+func (dt *DbfTable) SetDoubleFieldValueByName(row int, fieldName string, value float64) (err error) {
+	var b []byte
+	if b, err = dt.binaryField(row, fieldName, Double, Double7); err != nil {
+		return
+	}
+	dt.encodeFloat64(b, value)
+	dt.clearNullFlag(dt.record(row), dt.fieldMap[fieldName])
+	return
+}
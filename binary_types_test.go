@@ -0,0 +1,126 @@
+package godbf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createBinaryTypesTable(t *testing.T) *DbfTable {
+	table := New(nil)
+	return requireTestTable(t, table, 0,
+		table.AddIntegerField("INT"), table.AddCurrencyField("MONEY"),
+		table.AddDateTimeField("STAMP"), table.AddDoubleField("RATIO", 3))
+}
+
+func TestDbfTable_AddBinaryTypeFields(t *testing.T) {
+	table := createBinaryTypesTable(t)
+
+	expectedTypes := []DbaseDataType{Integer, Currency, DateTime, Double}
+	expectedLengths := []byte{4, 8, 8, 8}
+	for i, field := range table.Fields() {
+		require.Equal(t, field.FieldType(), expectedTypes[i])
+		require.Equal(t, field.Length(), expectedLengths[i])
+	}
+	require.EqualValues(t, table.lengthOfEachRecord, 1+4+8+8+8)
+
+	decimalPlaces, err := table.DecimalPlacesInField("RATIO")
+	require.Nil(t, err)
+	require.EqualValues(t, decimalPlaces, 3)
+}
+
+func TestDbfTable_BinaryTypeFields_StringValues(t *testing.T) {
+	table := createBinaryTypesTable(t)
+
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Equal(t, table.GetRowAsSlice(row), []string{"0", "0.0000", "", "0"})
+
+	require.Nil(t, table.SetFieldValueByName(row, "INT", "-123456"))
+	require.Nil(t, table.SetFieldValueByName(row, "MONEY", "-1234.56789"))
+	require.Nil(t, table.SetFieldValueByName(row, "STAMP", "19650315134501"))
+	require.Nil(t, table.SetFieldValueByName(row, "RATIO", "0.1"))
+
+	tableUnderTest, err := NewFromByteArray(table.dataStore, nil)
+	require.Nil(t, err)
+	require.Equal(t, tableUnderTest.GetRowAsSlice(row), []string{"-123456", "-1234.5679", "19650315134501", "0.1"})
+
+	require.Nil(t, tableUnderTest.SetFieldValueByName(row, "STAMP", "20200229"))
+	value, err := tableUnderTest.FieldValueByName(row, "STAMP")
+	require.Nil(t, err)
+	require.Equal(t, value, "20200229000000")
+
+	require.Nil(t, tableUnderTest.SetFieldValueByName(row, "STAMP", ""))
+	value, err = tableUnderTest.FieldValueByName(row, "STAMP")
+	require.Nil(t, err)
+	require.Equal(t, value, "")
+}
+
+func TestDbfTable_BinaryTypeFields_InvalidValues(t *testing.T) {
+	table := createBinaryTypesTable(t)
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	require.NotNil(t, table.SetFieldValueByName(row, "INT", "4294967296"))
+	require.NotNil(t, table.SetFieldValueByName(row, "INT", "1.5"))
+	require.NotNil(t, table.SetFieldValueByName(row, "MONEY", "lots"))
+	require.NotNil(t, table.SetFieldValueByName(row, "STAMP", "20201301"))
+	require.NotNil(t, table.SetFieldValueByName(row, "RATIO", "x"))
+}
+
+func TestDbfTable_BinaryTypeFields_TypedValues(t *testing.T) {
+	table := createBinaryTypesTable(t)
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	stamp := time.Date(2023, time.July, 4, 23, 59, 58, int(250*time.Millisecond), time.UTC)
+	require.Nil(t, table.SetDateTimeFieldValueByName(row, "STAMP", stamp))
+	require.Nil(t, table.SetCurrencyFieldValueByName(row, "MONEY", 123456))
+	require.Nil(t, table.SetFieldValueByName(row, "INT", "2147483647"))
+	require.Nil(t, table.SetFieldValueByName(row, "RATIO", "-2.5e-3"))
+
+	actualStamp, err := table.DateTimeFieldValueByName(row, "STAMP")
+	require.Nil(t, err)
+	require.Equal(t, actualStamp, stamp)
+
+	units, err := table.CurrencyFieldValueByName(row, "MONEY")
+	require.Nil(t, err)
+	require.EqualValues(t, units, 123456)
+	require.Equal(t, table.FieldValue(row, 1), "12.3456")
+
+	i, err := table.Int32FieldValueByName(row, "INT")
+	require.Nil(t, err)
+	require.EqualValues(t, i, 2147483647)
+
+	f, err := table.DoubleFieldValueByName(row, "RATIO")
+	require.Nil(t, err)
+	require.Equal(t, f, -0.0025)
+
+	_, err = table.DoubleFieldValueByName(row, "INT")
+	require.NotNil(t, err)
+	t.Log(err)
+
+	require.Nil(t, table.SetInt32FieldValueByName(row, "INT", -42))
+	require.Equal(t, table.FieldValue(row, 0), "-42")
+	require.Nil(t, table.SetDoubleFieldValueByName(row, "RATIO", 0.5))
+	require.Equal(t, table.FieldValue(row, 3), "0.5")
+	require.NotNil(t, table.SetInt32FieldValueByName(row, "RATIO", 1))
+
+	require.Nil(t, table.SetDateTimeFieldValueByName(row, "STAMP", time.Time{}))
+	actualStamp, err = table.DateTimeFieldValueByName(row, "STAMP")
+	require.Nil(t, err)
+	require.True(t, actualStamp.IsZero())
+}
+
+func TestDateTime_JulianDayEncoding(t *testing.T) {
+	table := New(nil)
+	b := make([]byte, 8)
+	table.encodeDateTime(b, time.Date(1970, time.January, 1, 0, 0, 1, 0, time.UTC))
+	require.Equal(t, b, []byte{0x8c, 0x3d, 0x25, 0x00, 0xe8, 0x03, 0x00, 0x00})
+
+	table.encodeDateTime(b, time.Date(1899, time.December, 30, 12, 0, 0, 0, time.UTC))
+	decoded, ok := table.decodeDateTime(b)
+	require.True(t, ok)
+	require.Equal(t, decoded, time.Date(1899, time.December, 30, 12, 0, 0, 0, time.UTC))
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/NovikovRoman/godbf"
+)
+
+// initialisms are the parts of field names that are written in upper case in Go names, as per Go conventions.
+var initialisms = map[string]bool{"ID": true, "URL": true, "UUID": true, "GUID": true, "HTML": true, "XML": true}
+
+// methodNames are the names of the generated methods, which struct fields must not take.
+var methodNames = []string{"Read", "Write"}
+
+// generator writes the Go source for the fields of a table.
+type generator struct {
+	packageName string
+	typeName    string
+	source      string // the name of the table file, for the header of the generated file
+	fields      []generatedField
+}
+
+// generatedField describes the struct field generated for a field of the table, and how its value is read and
+// written.
+type generatedField struct {
+	godbf.FieldDescriptor
+	goName  string
+	goType  string
+	getter  string // the by-name accessor of *godbf.DbfTable that reads the value
+	setter  string // the by-name accessor of *godbf.DbfTable that writes the value, empty if it is not written
+	guarded bool   // the getter fails for NULL values, which must be checked for first
+	valid   bool   // the getter also returns whether the value is valid, as for Logical fields
+	comment string
+}
+
+// newGenerator describes the struct for the fields given, named typeName in package packageName.
+func newGenerator(packageName, typeName, source string, fields []godbf.FieldDescriptor) (g *generator, err error) {
+	g = &generator{packageName: packageName, typeName: typeName, source: source}
+	used := make(map[string]bool)
+	for _, name := range methodNames {
+		used[name] = true
+	}
+
+	for _, field := range fields {
+		f := generatedField{FieldDescriptor: field, goName: uniqueName(goName(field.Name()), used)}
+		if err = f.describe(); err != nil {
+			return nil, err
+		}
+		g.fields = append(g.fields, f)
+	}
+	if len(g.fields) == 0 {
+		return nil, fmt.Errorf("table %s has no fields", source)
+	}
+	return
+}
+
+// describe sets the Go type and the accessors of the field, as per its type in the table.
+func (f *generatedField) describe() error {
+	if f.IsRaw() {
+		f.goType, f.getter = "[]byte", "RawFieldValueByName"
+		f.comment = fmt.Sprintf("type '%c' is not supported, the raw bytes are read and not written", f.FieldType())
+		return nil
+	}
+
+	switch f.FieldType() {
+	case godbf.Character:
+		f.goType, f.getter, f.setter = "string", "FieldValueByName", "SetFieldValueByName"
+	case godbf.Memo:
+		f.goType, f.getter, f.setter = "string", "MemoFieldValueByName", "SetFieldValueByName"
+	case godbf.General, godbf.Picture:
+		f.goType, f.getter, f.setter = "[]byte", "BinaryMemoFieldValueByName", "SetBinaryMemoFieldValueByName"
+	case godbf.Numeric, godbf.Float:
+		f.guarded = true
+		if f.DecimalPlaces() == 0 {
+			f.goType, f.getter, f.setter = "int64", "DecimalInt64FieldValueByName", "SetInt64FieldValueByName"
+		} else {
+			f.goType, f.getter, f.setter = "float64", "Float64FieldValueByName", "SetFloat64FieldValueByName"
+		}
+	case godbf.Logical:
+		f.goType, f.getter, f.setter, f.valid = "bool", "BoolFieldValueByName", "SetBoolFieldValueByName", true
+	case godbf.Date:
+		f.goType, f.getter, f.setter = "time.Time", "TimeFieldValueByName", "SetTimeFieldValueByName"
+	case godbf.DateTime, godbf.Timestamp:
+		f.goType, f.getter, f.setter = "time.Time", "DateTimeFieldValueByName", "SetDateTimeFieldValueByName"
+	case godbf.Currency:
+		f.goType, f.getter, f.setter = "int64", "CurrencyFieldValueByName", "SetCurrencyFieldValueByName"
+		f.comment = "in units of 1/10000th"
+	case godbf.Integer, godbf.AutoIncrement:
+		f.goType, f.getter, f.setter, f.guarded = "int32", "Int32FieldValueByName", "SetInt32FieldValueByName", true
+		if f.FieldType() == godbf.AutoIncrement || f.IsAutoIncrement() {
+			f.setter, f.comment = "", "assigned by the table, not written"
+		}
+	case godbf.Double, godbf.Double7:
+		f.goType, f.getter, f.setter, f.guarded = "float64", "DoubleFieldValueByName", "SetDoubleFieldValueByName", true
+	default:
+		return fmt.Errorf("type '%c' of field \"%s\" is not supported", f.FieldType(), f.Name())
+	}
+	return nil
+}
+
+// tag returns the struct tag of the field, which describes it well enough for godbf.NewFromStruct() to create it.
+func (f *generatedField) tag() string {
+	tag := f.Name()
+	if !f.IsRaw() {
+		tag += ",type=" + string(rune(f.FieldType()))
+		switch f.FieldType() {
+		case godbf.Character, godbf.Numeric, godbf.Float:
+			tag += ",len=" + strconv.Itoa(int(f.Length()))
+		}
+		if f.DecimalPlaces() > 0 && f.FieldType() != godbf.Character {
+			tag += ",dec=" + strconv.Itoa(int(f.DecimalPlaces()))
+		}
+	}
+	return fmt.Sprintf("`dbf:%s`", strconv.Quote(tag))
+}
+
+// generate returns the formatted Go source of the struct and its Read and Write methods.
+func (g *generator) generate() ([]byte, error) {
+	var buf bytes.Buffer
+	receiver := strings.ToLower(g.typeName[:1])
+	if !isASCIILetter(receiver) {
+		receiver = "r"
+	}
+
+	fmt.Fprintf(&buf, "// Code generated by dbfgen from %s. DO NOT EDIT.\n\n", g.source)
+	fmt.Fprintf(&buf, "package %s\n\n", g.packageName)
+	g.writeImports(&buf)
+
+	fmt.Fprintf(&buf, "// %s is a record of %s.\n", g.typeName, g.source)
+	fmt.Fprintf(&buf, "type %s struct {\n", g.typeName)
+	for _, f := range g.fields {
+		fmt.Fprintf(&buf, "%s %s %s", f.goName, f.goType, f.tag())
+		if f.comment != "" {
+			fmt.Fprintf(&buf, " // %s", f.comment)
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n\n")
+
+	fmt.Fprintf(&buf, "// Read sets the fields of %s to the values of the record at the given row of table.\n", receiver)
+	fmt.Fprintf(&buf, "func (%s *%s) Read(table *godbf.DbfTable, row int) (err error) {\n", receiver, g.typeName)
+	if g.hasGuardedFields() {
+		buf.WriteString("var isNull bool\n")
+	}
+	for _, f := range g.fields {
+		target := receiver + "." + f.goName
+		if f.valid {
+			target += ", _"
+		}
+		read := fmt.Sprintf("if %s, err = table.%s(row, %q); err != nil {\nreturn\n}\n", target, f.getter, f.Name())
+		if f.guarded {
+			fmt.Fprintf(&buf, "%s.%s = 0\n", receiver, f.goName)
+			fmt.Fprintf(&buf, "if isNull, err = table.FieldIsNullByName(row, %q); err != nil {\nreturn\n}\n", f.Name())
+			read = "if !isNull {\n" + read + "}\n"
+		}
+		buf.WriteString(read)
+	}
+	buf.WriteString("return\n}\n\n")
+
+	fmt.Fprintf(&buf, "// Write sets the values of the record at the given row of table to the fields of %s.\n", receiver)
+	fmt.Fprintf(&buf, "func (%s *%s) Write(table *godbf.DbfTable, row int) (err error) {\n", receiver, g.typeName)
+	for _, f := range g.fields {
+		if f.setter == "" {
+			continue
+		}
+		fmt.Fprintf(&buf, "if err = table.%s(row, %q, %s.%s); err != nil {\nreturn\n}\n",
+			f.setter, f.Name(), receiver, f.goName)
+	}
+	buf.WriteString("return\n}\n")
+
+	return format.Source(buf.Bytes())
+}
+
+// writeImports writes the import declaration for the packages used by the generated code.
+func (g *generator) writeImports(buf *bytes.Buffer) {
+	buf.WriteString("import (\n")
+	for _, f := range g.fields {
+		if f.goType == "time.Time" {
+			buf.WriteString("\"time\"\n\n")
+			break
+		}
+	}
+	buf.WriteString("\"github.com/NovikovRoman/godbf\"\n)\n\n")
+}
+
+func (g *generator) hasGuardedFields() bool {
+	for _, f := range g.fields {
+		if f.guarded {
+			return true
+		}
+	}
+	return false
+}
+
+// goName returns the exported Go name for a field or table name, such as CustomerID for CUSTOMER_ID. Names are split
+// into words at underscores and other characters that cannot be part of Go names.
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, word := range words {
+		upper := strings.ToUpper(word)
+		if initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(strings.ToLower(word))
+		if word != upper && word != strings.ToLower(word) {
+			// mixed case is kept as it is, as in CustomerName
+			runes = []rune(word)
+		}
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	s := b.String()
+	if s == "" {
+		return "Field"
+	}
+	if first := []rune(s)[0]; !unicode.IsUpper(first) {
+		// names starting with a digit, or with a letter that has no case, are not exported as they are
+		s = "F" + s
+	}
+	return s
+}
+
+// uniqueName returns name, with a number appended if it is already used, and records it as used.
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
+
+func isASCIILetter(s string) bool {
+	return len(s) == 1 && s[0] >= 'a' && s[0] <= 'z'
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NovikovRoman/godbf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_MatchesExample(t *testing.T) {
+	var out bytes.Buffer
+	require.Nil(t, run([]string{"-package", "example", "-type", "Customer", "testdata/customers.dbf"}, &out))
+
+	expected, err := os.ReadFile(filepath.Join("internal", "example", "customer_gen.go"))
+	require.Nil(t, err)
+	require.Equal(t, out.String(), string(expected))
+}
+
+func TestRun_Defaults(t *testing.T) {
+	t.Setenv("GOPACKAGE", "")
+	output := filepath.Join(t.TempDir(), "customers.go")
+	require.Nil(t, run([]string{"-o", output, "-encoding", "ibm866", "testdata/customers.dbf"}, nil))
+
+	source, err := os.ReadFile(output)
+	require.Nil(t, err)
+	require.Contains(t, string(source), "package main\n")
+	require.Contains(t, string(source), "type Customers struct {")
+
+	require.EqualError(t, run([]string{"-encoding", "nope", "testdata/customers.dbf"}, nil), "unknown encoding \"nope\"")
+	require.EqualError(t, run(nil, &bytes.Buffer{}), "expected the name of one table file, got 0 arguments")
+}
+
+func TestGenerator_UnusualFields(t *testing.T) {
+	table := godbf.New(nil, godbf.WithFormat(godbf.DBase7))
+	require.Nil(t, table.AddAutoIncrementField("ID"))
+	require.Nil(t, table.AddTextField("READ", 5))
+	require.Nil(t, table.AddTextField("read", 5))
+	require.Nil(t, table.AddTimestampField("2ND_STAMP"))
+
+	g, err := newGenerator("tables", "Log", "log.dbf", table.Fields())
+	require.Nil(t, err)
+	source, err := g.generate()
+	require.Nil(t, err)
+	require.Contains(t, string(source), "`dbf:\"ID,type=+\"` // assigned by the table, not written")
+	require.Contains(t, string(source), "l.Read2, err = table.FieldValueByName(row, \"READ\")")
+	require.Contains(t, string(source), "l.Read3, err = table.FieldValueByName(row, \"read\")")
+	require.Contains(t, string(source), "F2ndStamp time.Time `dbf:\"2ND_STAMP,type=@\"`")
+	require.NotContains(t, string(source), "SetInt32FieldValueByName")
+}
+
+func TestGoName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"CUST_ID":      "CustID",
+		"name":         "Name",
+		"CustomerName": "CustomerName",
+		"LAST SEEN":    "LastSeen",
+		"1ST":          "F1st",
+		"ИМЯ":          "Имя",
+		"__":           "Field",
+	} {
+		require.Equal(t, goName(name), expected)
+	}
+}
@@ -0,0 +1,145 @@
+// Code generated by dbfgen from customers.dbf. DO NOT EDIT.
+
+package example
+
+import (
+	"time"
+
+	"github.com/NovikovRoman/godbf"
+)
+
+// Customer is a record of customers.dbf.
+type Customer struct {
+	CustID   string    `dbf:"CUST_ID,type=C,len=8"`
+	Name     string    `dbf:"NAME,type=C,len=30"`
+	Visits   int64     `dbf:"VISITS,type=N,len=5"`
+	Balance  float64   `dbf:"BALANCE,type=N,len=12,dec=2"`
+	Rating   float64   `dbf:"RATING,type=F,len=6,dec=3"`
+	Active   bool      `dbf:"ACTIVE,type=L"`
+	Born     time.Time `dbf:"BORN,type=D"`
+	Notes    string    `dbf:"NOTES,type=M"`
+	Photo    []byte    `dbf:"PHOTO,type=P"`
+	Logins   int32     `dbf:"LOGINS,type=I"`
+	Credit   int64     `dbf:"CREDIT,type=Y"` // in units of 1/10000th
+	LastSeen time.Time `dbf:"LAST_SEEN,type=T"`
+	Score    float64   `dbf:"SCORE,type=B,dec=2"`
+}
+
+// Read sets the fields of c to the values of the record at the given row of table.
+func (c *Customer) Read(table *godbf.DbfTable, row int) (err error) {
+	var isNull bool
+	if c.CustID, err = table.FieldValueByName(row, "CUST_ID"); err != nil {
+		return
+	}
+	if c.Name, err = table.FieldValueByName(row, "NAME"); err != nil {
+		return
+	}
+	c.Visits = 0
+	if isNull, err = table.FieldIsNullByName(row, "VISITS"); err != nil {
+		return
+	}
+	if !isNull {
+		if c.Visits, err = table.DecimalInt64FieldValueByName(row, "VISITS"); err != nil {
+			return
+		}
+	}
+	c.Balance = 0
+	if isNull, err = table.FieldIsNullByName(row, "BALANCE"); err != nil {
+		return
+	}
+	if !isNull {
+		if c.Balance, err = table.Float64FieldValueByName(row, "BALANCE"); err != nil {
+			return
+		}
+	}
+	c.Rating = 0
+	if isNull, err = table.FieldIsNullByName(row, "RATING"); err != nil {
+		return
+	}
+	if !isNull {
+		if c.Rating, err = table.Float64FieldValueByName(row, "RATING"); err != nil {
+			return
+		}
+	}
+	if c.Active, _, err = table.BoolFieldValueByName(row, "ACTIVE"); err != nil {
+		return
+	}
+	if c.Born, err = table.TimeFieldValueByName(row, "BORN"); err != nil {
+		return
+	}
+	if c.Notes, err = table.MemoFieldValueByName(row, "NOTES"); err != nil {
+		return
+	}
+	if c.Photo, err = table.BinaryMemoFieldValueByName(row, "PHOTO"); err != nil {
+		return
+	}
+	c.Logins = 0
+	if isNull, err = table.FieldIsNullByName(row, "LOGINS"); err != nil {
+		return
+	}
+	if !isNull {
+		if c.Logins, err = table.Int32FieldValueByName(row, "LOGINS"); err != nil {
+			return
+		}
+	}
+	if c.Credit, err = table.CurrencyFieldValueByName(row, "CREDIT"); err != nil {
+		return
+	}
+	if c.LastSeen, err = table.DateTimeFieldValueByName(row, "LAST_SEEN"); err != nil {
+		return
+	}
+	c.Score = 0
+	if isNull, err = table.FieldIsNullByName(row, "SCORE"); err != nil {
+		return
+	}
+	if !isNull {
+		if c.Score, err = table.DoubleFieldValueByName(row, "SCORE"); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Write sets the values of the record at the given row of table to the fields of c.
+func (c *Customer) Write(table *godbf.DbfTable, row int) (err error) {
+	if err = table.SetFieldValueByName(row, "CUST_ID", c.CustID); err != nil {
+		return
+	}
+	if err = table.SetFieldValueByName(row, "NAME", c.Name); err != nil {
+		return
+	}
+	if err = table.SetInt64FieldValueByName(row, "VISITS", c.Visits); err != nil {
+		return
+	}
+	if err = table.SetFloat64FieldValueByName(row, "BALANCE", c.Balance); err != nil {
+		return
+	}
+	if err = table.SetFloat64FieldValueByName(row, "RATING", c.Rating); err != nil {
+		return
+	}
+	if err = table.SetBoolFieldValueByName(row, "ACTIVE", c.Active); err != nil {
+		return
+	}
+	if err = table.SetTimeFieldValueByName(row, "BORN", c.Born); err != nil {
+		return
+	}
+	if err = table.SetFieldValueByName(row, "NOTES", c.Notes); err != nil {
+		return
+	}
+	if err = table.SetBinaryMemoFieldValueByName(row, "PHOTO", c.Photo); err != nil {
+		return
+	}
+	if err = table.SetInt32FieldValueByName(row, "LOGINS", c.Logins); err != nil {
+		return
+	}
+	if err = table.SetCurrencyFieldValueByName(row, "CREDIT", c.Credit); err != nil {
+		return
+	}
+	if err = table.SetDateTimeFieldValueByName(row, "LAST_SEEN", c.LastSeen); err != nil {
+		return
+	}
+	if err = table.SetDoubleFieldValueByName(row, "SCORE", c.Score); err != nil {
+		return
+	}
+	return
+}
@@ -0,0 +1,82 @@
+package example
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NovikovRoman/godbf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomer_Read(t *testing.T) {
+	table, err := godbf.NewFromFile(filepath.Join("..", "..", "testdata", "customers.dbf"), nil)
+	require.Nil(t, err)
+
+	var customer Customer
+	require.Nil(t, customer.Read(table, 0))
+	require.Equal(t, customer, Customer{
+		CustID:   "C001",
+		Name:     "Ada Lovelace",
+		Visits:   12,
+		Balance:  1234.56,
+		Rating:   4.5,
+		Active:   true,
+		Born:     time.Date(1815, time.December, 10, 0, 0, 0, 0, time.Local),
+		Notes:    "first customer",
+		Photo:    []byte{0x89, 'P', 'N', 'G'},
+		Logins:   -3,
+		Credit:   999999,
+		LastSeen: time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC),
+		Score:    0.25,
+	})
+
+	// values of the first record are not kept for the NULL and blank values of the second
+	require.Nil(t, customer.Read(table, 1))
+	require.Equal(t, customer.Name, "Charles Babbage")
+	require.Zero(t, customer.Visits)
+	require.Zero(t, customer.Balance)
+	require.False(t, customer.Active)
+	require.True(t, customer.Born.IsZero())
+	require.Empty(t, customer.Photo)
+
+	// Numeric values with leading zeros are decimal, not octal
+	for stored, expected := range map[string]int64{"00012": 12, "00009": 9} {
+		row, err := table.AddNewRecord()
+		require.Nil(t, err)
+		require.Nil(t, table.SetFieldValueByName(row, "VISITS", stored))
+		require.Nil(t, customer.Read(table, row))
+		require.Equal(t, customer.Visits, expected, stored)
+	}
+}
+
+func TestCustomer_WriteToTableFromStruct(t *testing.T) {
+	table, err := godbf.NewFromStruct(Customer{}, nil, godbf.WithFormat(godbf.VisualFoxPro))
+	require.Nil(t, err)
+
+	source, err := godbf.NewFromFile(filepath.Join("..", "..", "testdata", "customers.dbf"), nil)
+	require.Nil(t, err)
+	fields := table.Fields()
+	for i, field := range source.Fields() {
+		require.Equal(t, fields[i].Name(), field.Name())
+		require.Equal(t, fields[i].FieldType(), field.FieldType())
+		require.Equal(t, fields[i].Length(), field.Length())
+		require.Equal(t, fields[i].DecimalPlaces(), field.DecimalPlaces())
+	}
+
+	var customer Customer
+	require.Nil(t, customer.Read(source, 0))
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Nil(t, customer.Write(table, row))
+
+	fileName := filepath.Join(t.TempDir(), "copy.dbf")
+	require.Nil(t, table.Save(fileName, os.ModePerm))
+	saved, err := godbf.NewFromFile(fileName, nil)
+	require.Nil(t, err)
+
+	var copied Customer
+	require.Nil(t, copied.Read(saved, row))
+	require.Equal(t, copied, customer)
+}
@@ -0,0 +1,5 @@
+// Package example holds the code generated by dbfgen for the customers.dbf test table, so that it is compiled and
+// tested along with the generator.
+package example
+
+//go:generate go run github.com/NovikovRoman/godbf/cmd/dbfgen -o customer_gen.go -type Customer ../../testdata/customers.dbf
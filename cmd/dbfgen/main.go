@@ -0,0 +1,98 @@
+// Command dbfgen generates a Go struct for the records of a dBase table, with `dbf` struct tags and typed Read and
+// Write methods that access the table's fields without reflection.
+//
+// Usage:
+//
+//	dbfgen [-o output.go] [-package name] [-type Name] [-encoding name] table.dbf
+//
+// The struct is named after the table file unless -type is given, and is in the package named by -package, by the
+// GOPACKAGE environment variable set by go generate, or else main. The source is written to standard output unless
+// -o is given. The encoding, such as ibm866 or windows-1251, is used for the field names, and is detected from the
+// table when not given.
+//
+// Each field of the table is mapped to a Go type suited to its dBase type: Character and Memo fields to string,
+// Numeric and Float fields to int64 when they have no decimal places and to float64 otherwise, Logical fields to bool,
+// Date and DateTime fields to time.Time, Integer fields to int32, Double fields to float64, Currency fields to int64
+// in units of 1/10000th, and General and Picture fields to []byte. NULL values are read as zero values.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/NovikovRoman/godbf"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "dbfgen:", err)
+		os.Exit(1)
+	}
+}
+
+// run generates the source for the table named by the arguments given, writing it to stdout unless -o is given.
+func run(args []string, stdout io.Writer) (err error) {
+	flags := flag.NewFlagSet("dbfgen", flag.ContinueOnError)
+	output := flags.String("o", "", "write the generated source to this file rather than standard output")
+	packageName := flags.String("package", "", "package of the generated source (default $GOPACKAGE or main)")
+	typeName := flags.String("type", "", "name of the generated struct (default from the table file name)")
+	encodingName := flags.String("encoding", "", "encoding of the table, such as ibm866 (default detected)")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: dbfgen [flags] table.dbf")
+		flags.PrintDefaults()
+	}
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected the name of one table file, got %d arguments", flags.NArg())
+	}
+	fileName := flags.Arg(0)
+
+	enc := godbf.Auto(nil)
+	if *encodingName != "" {
+		var e encoding.Encoding
+		if e, err = htmlindex.Get(*encodingName); err != nil {
+			return fmt.Errorf("unknown encoding \"%s\"", *encodingName)
+		}
+		enc = e
+	}
+
+	if *packageName == "" {
+		*packageName = os.Getenv("GOPACKAGE")
+		if *packageName == "" {
+			*packageName = "main"
+		}
+	}
+	base := filepath.Base(fileName)
+	if *typeName == "" {
+		*typeName = goName(strings.TrimSuffix(base, filepath.Ext(base)))
+	}
+
+	var table *godbf.DbfTable
+	if table, err = godbf.NewFromFile(fileName, enc); err != nil {
+		return
+	}
+
+	var g *generator
+	if g, err = newGenerator(*packageName, *typeName, base, table.Fields()); err != nil {
+		return
+	}
+	var source []byte
+	if source, err = g.generate(); err != nil {
+		return
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(source)
+		return
+	}
+	return os.WriteFile(*output, source, 0644)
+}
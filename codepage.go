@@ -0,0 +1,270 @@
+package godbf
+
+import (
+	"reflect"
+	"sync"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// languageDriverCodePages maps the language driver IDs of byte 29 of the header to the code pages they use, as per the
+// dBase and FoxPro language driver tables. Several drivers share a code page, differing only in their collation.
+var languageDriverCodePages = map[byte]int{
+	0x01: 437,   // US MS-DOS
+	0x02: 850,   // International MS-DOS
+	0x03: 1252,  // Windows ANSI
+	0x04: 10000, // Standard Macintosh
+	0x08: 865,   // Danish OEM
+	0x09: 437,   // Dutch OEM
+	0x0A: 850,   // Dutch OEM (secondary)
+	0x0B: 437,   // Finnish OEM
+	0x0D: 437,   // French OEM
+	0x0E: 850,   // French OEM (secondary)
+	0x0F: 437,   // German OEM
+	0x10: 850,   // German OEM (secondary)
+	0x11: 437,   // Italian OEM
+	0x12: 850,   // Italian OEM (secondary)
+	0x13: 932,   // Japanese Shift-JIS
+	0x14: 850,   // Spanish OEM (secondary)
+	0x15: 437,   // Swedish OEM
+	0x16: 850,   // Swedish OEM (secondary)
+	0x17: 865,   // Norwegian OEM
+	0x18: 437,   // Spanish OEM
+	0x19: 437,   // English OEM (Britain)
+	0x1A: 850,   // English OEM (Britain, secondary)
+	0x1B: 437,   // English OEM (U.S.)
+	0x1C: 863,   // French OEM (Canada)
+	0x1D: 850,   // French OEM (secondary)
+	0x1F: 852,   // Czech OEM
+	0x22: 852,   // Hungarian OEM
+	0x23: 852,   // Polish OEM
+	0x24: 860,   // Portuguese OEM
+	0x25: 850,   // Portuguese OEM (secondary)
+	0x26: 866,   // Russian OEM
+	0x37: 850,   // English OEM (U.S., secondary)
+	0x40: 852,   // Romanian OEM
+	0x4D: 936,   // Chinese GBK (PRC)
+	0x4E: 949,   // Korean (ANSI/OEM)
+	0x4F: 950,   // Chinese Big5 (Taiwan)
+	0x50: 874,   // Thai (ANSI/OEM)
+	0x57: 1252,  // Current ANSI code page
+	0x58: 1252,  // Western European ANSI
+	0x59: 1252,  // Spanish ANSI
+	0x64: 852,   // Eastern European MS-DOS
+	0x65: 866,   // Russian MS-DOS
+	0x66: 865,   // Nordic MS-DOS
+	0x67: 861,   // Icelandic MS-DOS
+	0x68: 895,   // Kamenicky (Czech) MS-DOS
+	0x69: 620,   // Mazovia (Polish) MS-DOS
+	0x6A: 737,   // Greek MS-DOS (437G)
+	0x6B: 857,   // Turkish MS-DOS
+	0x6C: 863,   // French-Canadian MS-DOS
+	0x78: 950,   // Taiwan Big 5
+	0x79: 949,   // Hangul (Wansung)
+	0x7A: 936,   // PRC GBK
+	0x7B: 932,   // Japanese Shift-JIS
+	0x7C: 874,   // Thai Windows/MS-DOS
+	0x7D: 1255,  // Hebrew Windows
+	0x7E: 1256,  // Arabic Windows
+	0x86: 737,   // Greek OEM
+	0x87: 852,   // Slovenian OEM
+	0x88: 857,   // Turkish OEM
+	0x96: 10007, // Russian Macintosh
+	0x97: 10029, // Eastern European Macintosh
+	0x98: 10006, // Greek Macintosh
+	0xC8: 1250,  // Eastern European Windows
+	0xC9: 1251,  // Russian Windows
+	0xCA: 1254,  // Turkish Windows
+	0xCB: 1253,  // Greek Windows
+	0xCC: 1257,  // Baltic Windows
+	0xF0: 65001, // UTF-8, a non-standard marker unknown to dBase and FoxPro
+}
+
+// codePageEncodings maps the code pages of language drivers to their encodings. Code pages used by language drivers,
+// but missing here (such as 620 and 895), have no known implementation; they can be supplied via RegisterCodePage().
+var codePageEncodings = map[int]encoding.Encoding{
+	437:   charmap.CodePage437,
+	737:   codePage737,
+	850:   charmap.CodePage850,
+	852:   charmap.CodePage852,
+	857:   codePage857,
+	860:   charmap.CodePage860,
+	861:   codePage861,
+	863:   charmap.CodePage863,
+	865:   charmap.CodePage865,
+	866:   charmap.CodePage866,
+	874:   charmap.Windows874,
+	932:   japanese.ShiftJIS,
+	936:   simplifiedchinese.GBK,
+	949:   korean.EUCKR,
+	950:   traditionalchinese.Big5,
+	1250:  charmap.Windows1250,
+	1251:  charmap.Windows1251,
+	1252:  charmap.Windows1252,
+	1253:  charmap.Windows1253,
+	1254:  charmap.Windows1254,
+	1255:  charmap.Windows1255,
+	1256:  charmap.Windows1256,
+	1257:  charmap.Windows1257,
+	10000: charmap.Macintosh,
+	10007: charmap.MacintoshCyrillic,
+	65001: unicode.UTF8,
+}
+
+// codePageFileEncodings maps the code pages that no dBase or FoxPro language driver uses to their encodings, so that
+// they can be named by .cpg files. Tables created with them are written with language driver ID 0x00, and cannot be
+// read back with Auto() unless a .cpg file names their code page.
+var codePageFileEncodings = map[int]encoding.Encoding{
+	855:   charmap.CodePage855,
+	858:   charmap.CodePage858,
+	862:   charmap.CodePage862,
+	869:   codePage869,
+	1258:  charmap.Windows1258,
+	20866: charmap.KOI8R,
+	21866: charmap.KOI8U,
+	28591: charmap.ISO8859_1,
+	28592: charmap.ISO8859_2,
+	28593: charmap.ISO8859_3,
+	28594: charmap.ISO8859_4,
+	28595: charmap.ISO8859_5,
+	28596: charmap.ISO8859_6,
+	28597: charmap.ISO8859_7,
+	28598: charmap.ISO8859_8,
+	28599: charmap.ISO8859_9,
+	28603: charmap.ISO8859_13,
+	28605: charmap.ISO8859_15,
+}
+
+// preferredLanguageDrivers lists the language driver IDs written for tables created with the encoding of their code
+// page, where several drivers share it. These are the IDs recognised by FoxPro as well as dBase.
+var preferredLanguageDrivers = []byte{
+	0x01, 0x02, 0x03, 0x04, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6A, 0x6B, 0x6C,
+	0x78, 0x79, 0x7A, 0x7B, 0x7C, 0x7D, 0x7E, 0x96, 0x97, 0x98, 0xC8, 0xC9, 0xCA, 0xCB, 0xCC,
+}
+
+const (
+	defaultLanguageDriverID = 0x57 // current ANSI code page, written for tables without an encoding
+	noLanguageDriverID      = 0x00 // written for tables whose encoding has no language driver ID
+)
+
+// codePageRegistry keeps the correspondence between language driver IDs and encodings.
+type codePageRegistry struct {
+	sync.RWMutex
+	encodings map[byte]encoding.Encoding // encoding used for reading tables of each language driver ID
+	ids       []registeredCodePage       // language driver ID written for tables of each encoding
+}
+
+// registeredCodePage is the language driver ID written for tables created with an encoding. Encodings are kept in a
+// slice rather than as map keys, as not every encoding.Encoding is comparable.
+type registeredCodePage struct {
+	id  byte
+	enc encoding.Encoding
+}
+
+var codePages = newCodePageRegistry()
+
+func newCodePageRegistry() *codePageRegistry {
+	r := &codePageRegistry{encodings: make(map[byte]encoding.Encoding)}
+	for id := 0; id <= 0xFF; id++ {
+		if codePage, ok := languageDriverCodePages[byte(id)]; ok {
+			if enc, ok := codePageEncodings[codePage]; ok {
+				r.register(byte(id), enc)
+			}
+		}
+	}
+	for _, id := range preferredLanguageDrivers {
+		if enc, ok := r.encodings[id]; ok {
+			r.register(id, enc)
+		}
+	}
+	return r
+}
+
+// RegisterCodePage registers the encoding of tables with the given language driver ID, as stored in byte 29 of the
+// header, and the ID as the one written for tables created with the encoding. Earlier registrations of either are
+// replaced, including the built-in ones, so that code pages without a built-in implementation (such as 620 or
+// 895) can be supported, and that IDs can be chosen for other encodings. It panics if the encoding is nil.
+func RegisterCodePage(languageDriverID byte, enc encoding.Encoding) {
+	if enc == nil {
+		panic("godbf: RegisterCodePage encoding is nil")
+	}
+
+	codePages.Lock()
+	defer codePages.Unlock()
+	codePages.register(languageDriverID, enc)
+}
+
+func (r *codePageRegistry) register(id byte, enc encoding.Encoding) {
+	r.encodings[id] = enc
+	for i := range r.ids {
+		if sameEncoding(r.ids[i].enc, enc) {
+			r.ids[i].id = id
+			return
+		}
+	}
+	r.ids = append(r.ids, registeredCodePage{id: id, enc: enc})
+}
+
+// LanguageDriverEncoding returns the encoding used by the language driver of the given ID, as stored in byte 29 of the
+// header. Nil is returned if no encoding is registered for the ID.
+func LanguageDriverEncoding(languageDriverID byte) encoding.Encoding {
+	codePages.RLock()
+	defer codePages.RUnlock()
+	return codePages.encodings[languageDriverID]
+}
+
+// CodePageID returns the language driver ID written to byte 29 of the header of tables created with the encoding.
+// False is returned if no ID is registered for the encoding.
+func CodePageID(enc encoding.Encoding) (languageDriverID byte, ok bool) {
+	codePages.RLock()
+	defer codePages.RUnlock()
+	for _, registered := range codePages.ids {
+		if sameEncoding(registered.enc, enc) {
+			return registered.id, true
+		}
+	}
+	return
+}
+
+// codePageID returns the language driver ID to write for tables of the encoding. Tables without an encoding are
+// marked as using the current ANSI code page, and those with an encoding that has no ID as having no language driver,
+// so that readers do not decode them wrongly.
+func codePageID(enc encoding.Encoding) byte {
+	if enc == nil {
+		return defaultLanguageDriverID
+	}
+	if id, ok := CodePageID(enc); ok {
+		return id
+	}
+	return noLanguageDriverID
+}
+
+// sameEncoding returns true if both encodings are the same, comparing them only if their type permits it.
+func sameEncoding(a encoding.Encoding, b encoding.Encoding) (same bool) {
+	defer func() {
+		// comparable types may still hold incomparable values, such as in interface fields
+		if recover() != nil {
+			same = false
+		}
+	}()
+
+	ta, tb := reflect.TypeOf(a), reflect.TypeOf(b)
+	return ta != nil && ta == tb && ta.Comparable() && a == b
+}
+
+// registeredLanguageDrivers returns a copy of the encodings used for each registered language driver ID.
+func registeredLanguageDrivers() map[byte]encoding.Encoding {
+	codePages.RLock()
+	defer codePages.RUnlock()
+	encodings := make(map[byte]encoding.Encoding, len(codePages.encodings))
+	for id, enc := range codePages.encodings {
+		encodings[id] = enc
+	}
+	return encodings
+}
@@ -0,0 +1,145 @@
+package godbf
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestCodePageEncodings_SurviveWriteAndReadBack(t *testing.T) {
+	for codePage, enc := range codePageEncodings {
+		t.Run(fmt.Sprint(codePage), func(t *testing.T) {
+			id, ok := CodePageID(enc)
+			require.True(t, ok, "code page %d has no language driver ID", codePage)
+
+			sample := "Abc 123"
+			if cp, ok := enc.(interface{ DecodeByte(b byte) rune }); ok {
+				sample += string(cp.DecodeByte(0xC1))
+			}
+
+			table := New(enc)
+			require.EqualValues(t, id, table.LanguageDriverID())
+			require.Nil(t, table.AddTextField("TEXT", 10))
+			row, err := table.AddNewRecord()
+			require.Nil(t, err)
+			require.Nil(t, table.SetFieldValue(row, 0, sample))
+
+			fileName := filepath.Join(t.TempDir(), "table.dbf")
+			require.Nil(t, table.Save(fileName, 0644))
+
+			loaded, err := NewFromFile(fileName, Auto(nil))
+			require.Nil(t, err)
+			require.EqualValues(t, id, loaded.LanguageDriverID())
+			require.True(t, sameEncoding(enc, loaded.Encoding()))
+			require.Equal(t, sample, loaded.FieldValue(row, 0))
+		})
+	}
+}
+
+func TestCodePageFileEncodings_HaveNoLanguageDriver(t *testing.T) {
+	for codePage, enc := range codePageFileEncodings {
+		_, ok := CodePageID(enc)
+		require.False(t, ok, "code page %d", codePage)
+		require.True(t, sameEncoding(enc, encodingFromCodePageName(fmt.Sprint(codePage))), "code page %d", codePage)
+	}
+}
+
+func TestOEMCodePages(t *testing.T) {
+	for enc, expected := range map[encoding.Encoding]string{
+		codePage737: "Αθήνα",
+		codePage857: "İstanbul Şişli",
+		codePage861: "Þórður Ásgeirsson",
+		codePage869: "Ελλάδα Ώρα",
+	} {
+		encoded, err := enc.NewEncoder().String(expected)
+		require.Nil(t, err, "%v", enc)
+		decoded, err := enc.NewDecoder().String(encoded)
+		require.Nil(t, err, "%v", enc)
+		require.Equal(t, decoded, expected, "%v", enc)
+	}
+
+	encoded, err := codePage737.NewEncoder().String("Α")
+	require.Nil(t, err)
+	require.Equal(t, encoded, "\x80")
+
+	_, err = codePage861.NewEncoder().String("Ж")
+	require.NotNil(t, err)
+	replaced, err := encoding.ReplaceUnsupported(codePage861.NewEncoder()).String("aЖ")
+	require.Nil(t, err)
+	require.Equal(t, replaced, "a\x1A")
+
+	decoded, err := codePage857.NewDecoder().String("\xD5")
+	require.Nil(t, err)
+	require.Equal(t, decoded, "\uFFFD")
+}
+
+func TestRegisteredLanguageDrivers_MapBackToTheirEncoding(t *testing.T) {
+	for id, enc := range registeredLanguageDrivers() {
+		writtenID, ok := CodePageID(enc)
+		require.True(t, ok, "language driver 0x%02X", id)
+		require.True(t, sameEncoding(enc, LanguageDriverEncoding(writtenID)), "language driver 0x%02X", id)
+	}
+}
+
+func TestCodePageID_PrefersFoxProLanguageDrivers(t *testing.T) {
+	for enc, expected := range map[encoding.Encoding]byte{
+		charmap.CodePage437: 0x01,
+		charmap.Windows1252: 0x03,
+		charmap.CodePage866: 0x65,
+		charmap.CodePage852: 0x64,
+		charmap.Windows1251: 0xC9,
+		unicode.UTF8:        0xF0,
+	} {
+		id, ok := CodePageID(enc)
+		require.True(t, ok)
+		require.EqualValues(t, expected, id, "%v", enc)
+	}
+}
+
+func TestNew_UnregisteredEncoding_HasNoLanguageDriver(t *testing.T) {
+	require.EqualValues(t, noLanguageDriverID, New(charmap.ISO8859_2).LanguageDriverID())
+	require.EqualValues(t, defaultLanguageDriverID, New(nil).LanguageDriverID())
+}
+
+func TestRegisterCodePage(t *testing.T) {
+	defer func(previous *codePageRegistry) { codePages = previous }(codePages)
+	codePages = newCodePageRegistry()
+
+	require.Nil(t, LanguageDriverEncoding(0x68))
+	RegisterCodePage(0x68, charmap.ISO8859_2) // standing in for code page 895
+	require.Equal(t, charmap.ISO8859_2, LanguageDriverEncoding(0x68))
+	id, ok := CodePageID(charmap.ISO8859_2)
+	require.True(t, ok)
+	require.EqualValues(t, 0x68, id)
+
+	RegisterCodePage(0x1F, charmap.ISO8859_2)
+	id, _ = CodePageID(charmap.ISO8859_2)
+	require.EqualValues(t, 0x1F, id)
+	require.Equal(t, charmap.ISO8859_2, LanguageDriverEncoding(0x68))
+
+	require.Panics(t, func() { RegisterCodePage(0x68, nil) })
+}
+
+// incomparableEncoding is an encoding whose values cannot be compared with ==.
+type incomparableEncoding struct {
+	encoding.Encoding
+	aliases []string
+}
+
+func TestSameEncoding_IncomparableEncodings(t *testing.T) {
+	require.False(t, sameEncoding(incomparableEncoding{Encoding: charmap.CodePage437}, charmap.CodePage437))
+	require.False(t, sameEncoding(Auto(incomparableEncoding{}), Auto(incomparableEncoding{})))
+	require.True(t, sameEncoding(Auto(charmap.CodePage437), Auto(charmap.CodePage437)))
+	require.False(t, sameEncoding(nil, nil))
+
+	defer func(previous *codePageRegistry) { codePages = previous }(codePages)
+	codePages = newCodePageRegistry()
+	RegisterCodePage(0xFE, incomparableEncoding{Encoding: charmap.CodePage437})
+	_, ok := CodePageID(incomparableEncoding{Encoding: charmap.CodePage437})
+	require.False(t, ok)
+}
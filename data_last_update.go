@@ -7,9 +7,9 @@ import "time"
 // Because measures smaller than a day cannot be encoded, for any time.Time conversion, the 'time of day' for a given
 // encoded day is assumed to be 12:00:00AM of that day.
 //
-// Timezones are also not supported. All date manipulation done via this struct assume the time.Local location applies.
-// Callers should thus be careful to ensure that they are using time.Local as their location  when interfacing
-// with the various decorator methods.
+// Timezones are also not supported. All date manipulation done via this struct assume the location of the table applies,
+// which is time.Local unless set otherwise via SetLocation(). Callers should thus be careful to ensure that they are
+// using the same location when interfacing with the various decorator methods.
 //
 // The updateYear byte encodes the year number (0-255). The actual gregorian calendar year is derived by adding
 // 1900 to the byte's value. Consequently, the range of years supported is [1900-2155] inclusive.
@@ -21,12 +21,29 @@ type dateOfLastUpdate struct {
 	updateYear  uint8 // YY + yearOffset (1900) = actual year.
 	updateMonth uint8
 	updateDay   uint8
+
+	location *time.Location // location of dates, time.Local when nil
+}
+
+// Location returns the location that dates read from the table are returned in, and that the date of last update is
+// interpreted in. Unless set otherwise via SetLocation(), or the WithLocation() option, this is time.Local.
+func (ud *dateOfLastUpdate) Location() *time.Location {
+	if ud.location == nil {
+		return time.Local
+	}
+	return ud.location
+}
+
+// SetLocation sets the location that dates read from the table are returned in, and that the date of last update is
+// interpreted in. A nil location restores the default of time.Local.
+func (ud *dateOfLastUpdate) SetLocation(location *time.Location) {
+	ud.location = location
 }
 
-// RefreshLastUpdated refreshes the dateOfLastUpdate to the YYMMDD byte encoding of today, assuming the local timezone.
+// RefreshLastUpdated refreshes the dateOfLastUpdate to the YYMMDD byte encoding of today, in the table's location.
 // SetLastUpdated() is used by this method, and the same restrictions for it apply here.
 func (ud *dateOfLastUpdate) RefreshLastUpdated() {
-	ud.SetLastUpdated(time.Now())
+	ud.SetLastUpdated(time.Now().In(ud.Location()))
 }
 
 // SetLastUpdated sets the dateOfLastUpdate to the YYMMDD byte encoding of the time.Time specified.
@@ -49,14 +66,14 @@ func (ud *dateOfLastUpdate) SetLastUpdatedFromBytes(timeBytes []byte) {
 
 // LastUpdated interprets the byte trio in dateOfLastUpdate, returning as close a time.Time value as possible.
 // As no hours, minutes, seconds, etc.  are supported in the encoding, we assume 12:00:00AM for the return time.
-// Similarly, time.Local is assumed for the location.
+// Similarly, the table's location is assumed for the location.
 func (ud *dateOfLastUpdate) LastUpdated() time.Time {
 	updateTime := time.Date(
 		int(ud.updateYear)+yearOffset,
 		time.Month(ud.updateMonth),
 		int(ud.updateDay),
 		0, 0, 0, 0,
-		time.Local)
+		ud.Location())
 
 	return updateTime
 }
@@ -69,6 +86,6 @@ func (ud *dateOfLastUpdate) LowDefTime(highDefTime time.Time) time.Time {
 		highDefTime.Month(),
 		highDefTime.Day(),
 		0, 0, 0, 0,
-		time.Local)
+		ud.Location())
 	return lowDefTime
 }
@@ -0,0 +1,82 @@
+package godbf
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// blankDate is how some xBase implementations store a date that has not been set, rather than as blanks.
+const blankDate = "00000000"
+
+// TimeFieldValue returns the value of a Date field given row number and field index provided, as midnight of the date
+// in the table's location (see SetLocation). A blank date is returned as the zero time.Time, which can be detected with
+// IsZero(). An error is returned if the field is not a Date field, or if it holds an impossible date.
+func (dt *DbfTable) TimeFieldValue(row int, fieldIndex int) (value time.Time, err error) {
+	var field []byte
+	if field, err = dt.dateField(row, fieldIndex); err != nil {
+		return
+	}
+
+	s := strings.TrimSpace(strings.Trim(string(field), "\x00"))
+	if s == "" || s == blankDate {
+		return
+	}
+
+	if value, err = time.ParseInLocation(dateLayout, s, dt.Location()); err != nil {
+		err = fmt.Errorf("invalid date \"%s\" in field \"%s\"", s, dt.fields[fieldIndex].name)
+	}
+	return
+}
+
+// TimeFieldValueByName returns the value of a Date field given row number and name provided, as per TimeFieldValue().
+func (dt *DbfTable) TimeFieldValueByName(row int, fieldName string) (value time.Time, err error) {
+	if fieldIndex, entryFound := dt.fieldMap[fieldName]; entryFound {
+		return dt.TimeFieldValue(row, fieldIndex)
+	}
+	err = fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+	return
+}
+
+// SetTimeFieldValue sets the value of a Date field given row number and field index provided. The date of value in its
+// own location is stored, as the field records neither a time of day nor a time zone. The zero time.Time blanks the
+// field. An error is returned if the field is not a Date field, or if the year cannot be stored in 4 digits.
+func (dt *DbfTable) SetTimeFieldValue(row int, fieldIndex int, value time.Time) (err error) {
+	var field []byte
+	if field, err = dt.dateField(row, fieldIndex); err != nil {
+		return
+	}
+
+	if value.IsZero() {
+		fillFieldWithBlanks(field)
+		dt.clearNullFlag(dt.record(row), fieldIndex)
+		return
+	}
+
+	if value.Year() < 1 || value.Year() > 9999 {
+		return fmt.Errorf("year %d of date for field \"%s\" is out of range", value.Year(), dt.fields[fieldIndex].name)
+	}
+	copy(field, value.Format(dateLayout))
+	dt.clearNullFlag(dt.record(row), fieldIndex)
+	return
+}
+
+// SetTimeFieldValueByName sets the value of a Date field given row number and name provided, as per
+// SetTimeFieldValue().
+func (dt *DbfTable) SetTimeFieldValueByName(row int, fieldName string, value time.Time) (err error) {
+	if fieldIndex, entryFound := dt.fieldMap[fieldName]; entryFound {
+		return dt.SetTimeFieldValue(row, fieldIndex, value)
+	}
+	return fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+}
+
+// dateField returns the bytes of the Date field with the given index, for the record at the given row.
+func (dt *DbfTable) dateField(row int, fieldIndex int) (field []byte, err error) {
+	if dt.fields[fieldIndex].fieldType != Date {
+		err = fmt.Errorf("type of field \"%s\" is not Date", dt.fields[fieldIndex].name)
+		return
+	}
+
+	offset := dt.fieldOffset(fieldIndex)
+	return dt.record(row)[offset : offset+int(dt.fields[fieldIndex].length)], nil
+}
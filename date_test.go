@@ -0,0 +1,77 @@
+package godbf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newDateTestTable(t *testing.T, options ...TableOption) *DbfTable {
+	table := New(nil, options...)
+	return requireTestTable(t, table, 1, table.AddTextField("NAME", 8), table.AddDateField("DUE"))
+}
+
+func TestDbfTable_TimeFieldValue_RoundTrip(t *testing.T) {
+	table := newDateTestTable(t)
+
+	require.Nil(t, table.SetTimeFieldValueByName(0, "DUE", time.Date(2024, 2, 29, 23, 59, 0, 0, time.UTC)))
+	require.Equal(t, "20240229", table.FieldValue(0, 1))
+
+	value, err := table.TimeFieldValueByName(0, "DUE")
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local), value)
+
+	value, err = table.TimeFieldValue(0, 1)
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local), value)
+}
+
+func TestDbfTable_TimeFieldValue_Blank(t *testing.T) {
+	table := newDateTestTable(t)
+
+	value, err := table.TimeFieldValue(0, 1)
+	require.Nil(t, err)
+	require.True(t, value.IsZero())
+
+	require.Nil(t, table.SetFieldValue(0, 1, blankDate))
+	value, err = table.TimeFieldValue(0, 1)
+	require.Nil(t, err)
+	require.True(t, value.IsZero())
+
+	require.Nil(t, table.SetTimeFieldValue(0, 1, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+	require.Nil(t, table.SetTimeFieldValue(0, 1, time.Time{}))
+	require.Equal(t, "", table.FieldValue(0, 1))
+}
+
+func TestDbfTable_TimeFieldValue_Errors(t *testing.T) {
+	table := newDateTestTable(t)
+
+	require.Nil(t, table.SetFieldValue(0, 1, "20230229"))
+	_, err := table.TimeFieldValue(0, 1)
+	require.EqualError(t, err, "invalid date \"20230229\" in field \"DUE\"")
+
+	_, err = table.TimeFieldValueByName(0, "NAME")
+	require.EqualError(t, err, "type of field \"NAME\" is not Date")
+	_, err = table.TimeFieldValueByName(0, "MISSING")
+	require.NotNil(t, err)
+
+	require.NotNil(t, table.SetTimeFieldValueByName(0, "NAME", time.Now()))
+	require.NotNil(t, table.SetTimeFieldValueByName(0, "MISSING", time.Now()))
+	require.NotNil(t, table.SetTimeFieldValue(0, 1, time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestDbfTable_Location(t *testing.T) {
+	tokyo := time.FixedZone("Tokyo", 9*60*60)
+	table := newDateTestTable(t, WithLocation(tokyo))
+	require.Equal(t, tokyo, table.Location())
+	require.Equal(t, tokyo, table.LastUpdated().Location())
+
+	require.Nil(t, table.SetFieldValue(0, 1, "20240102"))
+	value, err := table.TimeFieldValue(0, 1)
+	require.Nil(t, err)
+	require.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, tokyo), value)
+
+	table.SetLocation(nil)
+	require.Equal(t, time.Local, table.Location())
+}
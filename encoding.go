@@ -2,53 +2,99 @@ package godbf
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
 )
 
-// codePageID returns the correspondence between the encoder and the code page identifier of the file.
-func codePageID(enc encoding.Encoding) byte {
-	const defCodePageID = 0x57 // ANSI
-	if enc == nil {
-		return defCodePageID
-	}
-
-	switch fmt.Sprint(enc) {
-	case "Big5":
-		return 0x78
-	case "ISO 8859-2":
-		return 0x1b
-	case "IBM Code Page 865":
-		return 0x66
-	case "IBM Code Page 863":
-		return 0x6c
-	case "IBM Code Page 852":
-		return 0x87
-	case "IBM Code Page 860":
-		return 0x24
-	case "IBM Code Page 866":
-		return 0x65
-	case "IBM Code Page 850":
-		return 0x37
-	case "Windows 874":
-		return 0x7c
-	case "ISO 8859-9":
-		return 0x88
-	case "Windows 1250":
-		return 0xc8
-	case "Windows 1251":
-		return 0xc9
-	case "Windows 1252":
-		return 0x59
-	case "Windows 1253":
-		return 0xcb
-	case "Windows 1254":
-		return 0xca
-	case "Windows 1257":
-		return 0xcc
-	case "Shift JIS":
-		return 0x7b
-	}
-
-	return defCodePageID
+// autoEncoding is the encoding.Encoding returned by Auto(). It stands in for its fallback when used as an encoding.
+type autoEncoding struct {
+	fallback encoding.Encoding
+}
+
+// Auto returns an encoding.Encoding which, when supplied to NewFromFile() or NewFromByteArray(), has the table's
+// encoding detected rather than expected. The encoding of the table's language driver ID is used if known. Otherwise,
+// NewFromFile() reads the code page from a .cpg file alongside the table file, if present. Failing both, the fallback
+// encoding given is used, which may be nil.
+//
+// The language driver ID 0x57 is treated as unknown, even though LanguageDriverEncoding() maps it to Windows-1252: it
+// stands for the current ANSI code page of the system that wrote the table, as written by ArcGIS and QGIS, and by
+// New() for tables without an encoding, so the .cpg file or fallback decide the encoding of such tables instead.
+func Auto(fallback encoding.Encoding) encoding.Encoding {
+	return autoEncoding{fallback: fallback}
+}
+
+func (ae autoEncoding) NewDecoder() *encoding.Decoder {
+	if ae.fallback == nil {
+		return encoding.Nop.NewDecoder()
+	}
+	return ae.fallback.NewDecoder()
+}
+
+func (ae autoEncoding) NewEncoder() *encoding.Encoder {
+	if ae.fallback == nil {
+		return encoding.Nop.NewEncoder()
+	}
+	return ae.fallback.NewEncoder()
+}
+
+func (ae autoEncoding) String() string {
+	return fmt.Sprintf("Auto(%v)", ae.fallback)
+}
+
+// resolveEncoding returns the encoding to use for the table whose header is given, detecting it from the language
+// driver ID if the encoding supplied was created by Auto(). Other encodings are returned as they are.
+func resolveEncoding(enc encoding.Encoding, header []byte) encoding.Encoding {
+	auto, ok := enc.(autoEncoding)
+	if !ok {
+		return enc
+	}
+	if len(header) > languageDriverIDIndex && header[languageDriverIDIndex] != defaultLanguageDriverID {
+		if detected := LanguageDriverEncoding(header[languageDriverIDIndex]); detected != nil {
+			return detected
+		}
+	}
+	return auto.fallback
+}
+
+// withCodePageFile returns an encoding created by Auto() that falls back to the encoding named by the content of a
+// .cpg file, if it names one, before the encoding's own fallback. Other encodings are returned as they are.
+func withCodePageFile(enc encoding.Encoding, codePageFileContent []byte) encoding.Encoding {
+	if _, ok := enc.(autoEncoding); !ok || codePageFileContent == nil {
+		return enc
+	}
+	if named := encodingFromCodePageName(string(codePageFileContent)); named != nil {
+		return Auto(named)
+	}
+	return enc
+}
+
+// encodingFromCodePageName returns the encoding named by the content of a .cpg file, such as "UTF-8", "1251",
+// "ANSI 1252", "88591" or "ISO-8859-1". Nil is returned if the encoding is not known.
+func encodingFromCodePageName(name string) encoding.Encoding {
+	name = strings.TrimSpace(name)
+	if enc, err := htmlindex.Get(name); err == nil {
+		return enc
+	}
+
+	number := strings.ToUpper(name)
+	for _, prefix := range []string{"ANSI", "OEM", "CP", "IBM"} {
+		number = strings.TrimSpace(strings.TrimPrefix(number, prefix))
+	}
+	if strings.HasPrefix(number, "8859") && len(number) > 4 {
+		// ISO 8859 parts, abbreviated as in "88595", have code pages numbered from 28591
+		if part, err := strconv.Atoi(number[4:]); err == nil {
+			number = strconv.Itoa(28590 + part)
+		}
+	}
+	codePage, err := strconv.Atoi(number)
+	if err != nil {
+		return nil
+	}
+	if enc, ok := codePageEncodings[codePage]; ok {
+		return enc
+	}
+	return codePageFileEncodings[codePage]
 }
@@ -0,0 +1,122 @@
+package godbf
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestLanguageDriverEncoding(t *testing.T) {
+	require.Equal(t, charmap.CodePage866, LanguageDriverEncoding(0x26))
+	require.Equal(t, charmap.CodePage866, LanguageDriverEncoding(0x65))
+	require.Equal(t, charmap.Windows1251, LanguageDriverEncoding(0xC9))
+	require.Equal(t, charmap.Windows1252, LanguageDriverEncoding(0x57))
+	require.Equal(t, codePage857, LanguageDriverEncoding(0x6B))
+	require.Nil(t, LanguageDriverEncoding(0x00))
+	require.Nil(t, LanguageDriverEncoding(0x68), "code page 895 has no implementation")
+}
+
+func TestEncodingFromCodePageName(t *testing.T) {
+	for name, expected := range map[string]encoding.Encoding{
+		"UTF-8":        unicode.UTF8,
+		"1251\r\n":     charmap.Windows1251,
+		"ANSI 1252":    charmap.Windows1252,
+		"OEM 866":      charmap.CodePage866,
+		"88595":        charmap.ISO8859_5,
+		"ISO-8859-2":   charmap.ISO8859_2,
+		"windows-1250": charmap.Windows1250,
+		"unheard of":   nil,
+	} {
+		require.Equal(t, expected, encodingFromCodePageName(name), name)
+	}
+}
+
+func TestNewFromByteArray_Auto_DetectsLanguageDriver(t *testing.T) {
+	table := New(charmap.Windows1251)
+	require.Nil(t, table.AddTextField("NAME", 10))
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Nil(t, table.SetFieldValue(row, 0, "Привет"))
+
+	loaded, err := NewFromByteArray(table.dataStore, Auto(charmap.CodePage437))
+	require.Nil(t, err)
+	require.Equal(t, charmap.Windows1251, loaded.Encoding())
+	require.Equal(t, "Привет", loaded.FieldValue(row, 0))
+}
+
+func TestNewFromFile_Auto_FallsBackToCodePageFileThenDefault(t *testing.T) {
+	table := New(charmap.CodePage866)
+	require.Nil(t, table.AddTextField("NAME", 10))
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Nil(t, table.SetFieldValue(row, 0, "Привет"))
+	table.dataStore[languageDriverIDIndex] = 0x00 // no language driver
+
+	fileName := filepath.Join(t.TempDir(), "table.dbf")
+	require.Nil(t, table.Save(fileName, 0644))
+
+	loaded, err := NewFromFile(fileName, Auto(charmap.Windows1252))
+	require.Nil(t, err)
+	require.Equal(t, charmap.Windows1252, loaded.Encoding())
+
+	require.Nil(t, ioutil.WriteFile(memoFileName(fileName, codePageFileExtension), []byte("866"), 0644))
+	loaded, err = NewFromFile(fileName, Auto(charmap.Windows1252))
+	require.Nil(t, err)
+	require.Equal(t, charmap.CodePage866, loaded.Encoding())
+	require.Equal(t, "Привет", loaded.FieldValue(row, 0))
+}
+
+func TestNewFromFile_Auto_CurrentANSICodePageUsesCodePageFile(t *testing.T) {
+	table := New(nil)
+	require.Nil(t, table.AddTextField("NAME", 10))
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	encoded, err := charmap.Windows1251.NewEncoder().String("Привет")
+	require.Nil(t, err)
+	require.Nil(t, table.SetFieldValue(row, 0, encoded))
+	require.Equal(t, byte(0x57), table.dataStore[languageDriverIDIndex])
+
+	fileName := filepath.Join(t.TempDir(), "a.dbf")
+	require.Nil(t, table.Save(fileName, 0644))
+
+	loaded, err := NewFromFile(fileName, Auto(charmap.Windows1251))
+	require.Nil(t, err)
+	require.Equal(t, charmap.Windows1251, loaded.Encoding())
+	require.Equal(t, "Привет", loaded.FieldValue(row, 0))
+
+	require.Nil(t, ioutil.WriteFile(memoFileName(fileName, codePageFileExtension), []byte("1251"), 0644))
+	loaded, err = NewFromFile(fileName, Auto(nil))
+	require.Nil(t, err)
+	require.Equal(t, charmap.Windows1251, loaded.Encoding())
+	require.Equal(t, "Привет", loaded.FieldValue(row, 0))
+}
+
+func TestAuto_CurrentANSICodePageUsesFallback(t *testing.T) {
+	table := New(nil)
+	require.Nil(t, table.AddTextField("NAME", 10))
+	require.Equal(t, byte(0x57), table.dataStore[languageDriverIDIndex])
+
+	loaded, err := NewFromByteArray(table.dataStore, Auto(nil))
+	require.Nil(t, err)
+	require.Nil(t, loaded.Encoding(), "Windows-1252 is not assumed for language driver 0x57")
+
+	loaded, err = NewFromByteArray(table.dataStore, Auto(charmap.CodePage866))
+	require.Nil(t, err)
+	require.Equal(t, charmap.CodePage866, loaded.Encoding())
+
+	fileName := filepath.Join(t.TempDir(), "table.dbf")
+	require.Nil(t, table.Save(fileName, 0644))
+	loaded, err = NewFromFile(fileName, Auto(nil))
+	require.Nil(t, err)
+	require.Nil(t, loaded.Encoding(), "no .cpg file alongside the table")
+}
+
+func TestNew_Auto_UsesFallback(t *testing.T) {
+	require.Equal(t, charmap.CodePage866, New(Auto(charmap.CodePage866)).Encoding())
+	require.Nil(t, New(Auto(nil)).Encoding())
+}
@@ -0,0 +1,142 @@
+// Package expression parses and evaluates the xBase expressions of dBase and FoxPro, as used by the filters and index
+// keys of tables, such as UPPER(LASTNAME)+DTOS(BIRTH) or AMOUNT > 100 .AND. !DELETED().
+//
+// Expressions are made of fields, literals, operators and function calls. Literals are numbers, strings in single or
+// double quotes or square brackets, the logical values .T. and .F. (or .Y. and .N.), and dates such as {^2024-01-31},
+// {01/31/2024} and the empty date {}. The operators are, from lowest to highest precedence:
+//
+//	.OR.
+//	.AND.
+//	.NOT. !
+//	= == <> # != < <= > >= $
+//	+ -
+//	* / %
+//	** ^
+//	unary + -
+//
+// Values are of four types, returned by Evaluate() as Go values: character values as string, numeric values as
+// float64, logical values as bool, and dates as time.Time, at midnight UTC for Date fields and the zero time.Time for
+// empty dates. As in xBase, + concatenates strings and - concatenates them with the trailing blanks of the first moved
+// to the end, = compares strings as SET EXACT OFF does, being true if the first starts with the second, while ==
+// compares them exactly, and $ is true if the first string is contained in the second. Numbers are added to dates and
+// subtracted from them as days, and subtracting dates gives the number of days between them.
+//
+// Fields are named regardless of case, optionally prefixed by the alias of their table as in CUSTOMER->NAME, the
+// alias being ignored. Their values are as per their types in Fields(): Character fields are padded with blanks to
+// their length, as xBase compares and concatenates them, and Memo fields are strings. Numeric, Float, Integer,
+// Currency and Double fields are numbers, Logical fields are logical, and Date and DateTime fields are dates. NULL and
+// blank values are the blank values of their types, such as 0 for Numeric fields.
+//
+// The functions are:
+//
+//	UPPER(c), LOWER(c)              the string in upper or lower case
+//	TRIM(c), RTRIM(c)               the string without trailing blanks
+//	LTRIM(c), ALLTRIM(c)            the string without leading blanks, or without leading and trailing blanks
+//	SUBSTR(c, start[, length])      the part of the string from the 1-based start, to its end or of the length given
+//	LEFT(c, n), RIGHT(c, n)         the first or last n characters of the string
+//	LEN(c)                          the number of characters of the string
+//	SPACE(n)                        a string of n blanks
+//	STR(n[, length[, decimals]])    the number right-aligned in a string of 10 or the given length, with no or the
+//	                                given decimal places, or asterisks if it does not fit
+//	VAL(c)                          the number at the start of the string, or 0 if there is none
+//	DTOS(d)                         the date as YYYYMMDD, or 8 blanks for the empty date
+//	DTOC(d)                         the date as MM/DD/YYYY, or blanks for the empty date
+//	CTOD(c)                         the date of a string as MM/DD/YYYY or MM/DD/YY, or the empty date if it is invalid
+//	YEAR(d), MONTH(d), DAY(d)       the parts of the date, or 0 for the empty date
+//	ABS(n), INT(n), ROUND(n, dec)   the absolute value, integer part or rounded value of the number
+//	MIN(x, y), MAX(x, y)            the lesser or greater of two values of the same type
+//	EMPTY(x)                        .T. for blank strings, 0, .F. and the empty date
+//	IIF(l, x, y)                    x if l is true, or else y, evaluating only the one returned
+//	DELETED()                       .T. if the record is marked as deleted
+//	RECNO()                         the 1-based number of the record
+//
+// Function names may be abbreviated to their first four letters, as in dBase. As in Visual FoxPro, lengths and
+// positions are at most 16777184, and numbers of decimal places at most 18.
+package expression
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NovikovRoman/godbf"
+)
+
+// Expression is a parsed xBase expression, which can be evaluated for the records of any table that has the fields it
+// names. It is safe for concurrent use.
+type Expression struct {
+	source string
+	root   node
+}
+
+// Parse parses the xBase expression given.
+func Parse(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != endToken {
+		return nil, p.unexpected("operator")
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// MustParse is like Parse() but panics if the expression cannot be parsed. It simplifies the initialisation of global
+// variables holding expressions.
+func MustParse(source string) *Expression {
+	e, err := Parse(source)
+	if err != nil {
+		panic(fmt.Sprintf("expression: Parse(%q): %v", source, err))
+	}
+	return e
+}
+
+// String returns the source of the expression.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate evaluates the expression for the record at the given row of the table, returning a string, a float64, a
+// bool or a time.Time. An error is returned if a field does not exist, or if an operator or function is given values
+// of types it does not accept.
+func (e *Expression) Evaluate(table *godbf.DbfTable, row int) (interface{}, error) {
+	if row < 0 || row >= table.NumberOfRecords() {
+		return nil, fmt.Errorf("row %d does not exist", row)
+	}
+	return e.root.evaluate(&context{table: table, row: row})
+}
+
+// Matches evaluates the expression, which must be logical, for the record at the given row of the table, as for a
+// filter.
+func (e *Expression) Matches(table *godbf.DbfTable, row int) (bool, error) {
+	value, err := e.Evaluate(table, row)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %s is %s, not logical", e.source, typeName(value))
+	}
+	return b, nil
+}
+
+// context is the record for which an expression is evaluated.
+type context struct {
+	table *godbf.DbfTable
+	row   int
+}
+
+// fieldIndex returns the index of the field with the given name, regardless of case.
+func (c *context) fieldIndex(name string) (int, bool) {
+	for i, field := range c.table.Fields() {
+		if strings.EqualFold(field.Name(), name) {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,165 @@
+package expression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NovikovRoman/godbf"
+	"github.com/stretchr/testify/require"
+)
+
+func createPeopleTable(t *testing.T) *godbf.DbfTable {
+	table := godbf.New(nil, godbf.WithFormat(godbf.VisualFoxPro))
+	require.Nil(t, table.AddTextField("LASTNAME", 10))
+	require.Nil(t, table.AddTextField("FIRSTNAME", 10))
+	require.Nil(t, table.AddDateField("BIRTH"))
+	require.Nil(t, table.AddNumberField("AMOUNT", 8, 2))
+	require.Nil(t, table.AddBooleanField("ACTIVE"))
+	require.Nil(t, table.AddIntegerField("VISITS"))
+	require.Nil(t, table.AddMemoField("NOTES"))
+	require.Nil(t, table.AddDateTimeField("SEEN"))
+	require.Nil(t, table.AddPictureField("PHOTO"))
+	require.Nil(t, table.SetFieldNullable("AMOUNT"))
+
+	for _, values := range [][]string{
+		{"Lovelace", "Ada", "18151210", "150.50", "T", "12", "first", "20240301123000"},
+		{"babbage", "Charles", "", "", "F", "0", "", ""},
+	} {
+		row, err := table.AddNewRecord()
+		require.Nil(t, err)
+		for i, value := range values {
+			require.Nil(t, table.SetFieldValue(row, i, value))
+		}
+	}
+	require.Nil(t, table.SetFieldNullByName(1, "AMOUNT"))
+	require.Nil(t, table.DeleteRecord(1))
+	return table
+}
+
+func TestExpression_Evaluate(t *testing.T) {
+	table := createPeopleTable(t)
+	birth := time.Date(1815, time.December, 10, 0, 0, 0, 0, time.UTC)
+
+	for source, expected := range map[string]interface{}{
+		"LASTNAME":                                "Lovelace  ",
+		"UPPER(LASTNAME)+DTOS(BIRTH)":             "LOVELACE  18151210",
+		"upper(people->lastname) - firstname":     "LOVELACEAda         ",
+		"TRIM(FIRSTNAME) + ' ' + TRIM(LASTNAME)":  "Ada Lovelace",
+		"AMOUNT > 100 .AND. !DELETED()":           true,
+		"AMOUNT > 100 .and. .not. active":         false,
+		"AMOUNT * 2 - VISITS ^ 2 / 4 % 5":         300.0,
+		"-AMOUNT + 1":                             -149.5,
+		"2 ** 3 ** 2":                             64.0,
+		"(1 + 2) * 3":                             9.0,
+		"LASTNAME = 'Love'":                       true,
+		"LASTNAME == 'Love'":                      false,
+		"TRIM(LASTNAME) == 'Lovelace'":            true,
+		"'Love' = LASTNAME":                       false,
+		"LASTNAME <> 'Love'":                      false,
+		"LASTNAME # 'Ada'":                        true,
+		"LASTNAME >= 'Love' .AND. LASTNAME < 'M'": true,
+		"'lace' $ LASTNAME":                       true,
+		"BIRTH":                                   birth,
+		"BIRTH + 1":                               birth.AddDate(0, 0, 1),
+		"BIRTH - {^1815-12-01}":                   9.0,
+		"BIRTH = CTOD('12/10/1815')":              true,
+		"BIRTH < {01/01/1900}":                    true,
+		"SEEN":                                    time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC),
+		"NOTES + [!]":                             "first!",
+		"ACTIVE = .T. .OR. 1/0 = 1":               true,
+		"IIF(ACTIVE, 'yes', 1/0)":                 "yes",
+		"RECNO()":                                 1.0,
+		"STR(VISITS, 5) + STR(AMOUNT, 8, 1)":      "   12   150.5",
+	} {
+		e, err := Parse(source)
+		require.Nil(t, err, source)
+		value, err := e.Evaluate(table, 0)
+		require.Nil(t, err, source)
+		require.Equal(t, value, expected, source)
+	}
+
+	// blank and NULL values are the blank values of their types
+	for source, expected := range map[string]interface{}{
+		"AMOUNT":                   0.0,
+		"BIRTH":                    time.Time{},
+		"DTOS(BIRTH)":              "        ",
+		"EMPTY(BIRTH)":             true,
+		"SEEN - BIRTH":             0.0,
+		"NOTES":                    "",
+		"DELETED()":                true,
+		"RECNO()":                  2.0,
+		"UPPER(LASTNAME) = 'BABB'": true,
+	} {
+		value, err := MustParse(source).Evaluate(table, 1)
+		require.Nil(t, err, source)
+		require.Equal(t, value, expected, source)
+	}
+}
+
+func TestExpression_Matches(t *testing.T) {
+	table := createPeopleTable(t)
+	filter := MustParse("AMOUNT > 100 .AND. !DELETED()")
+
+	var matches []int
+	for row := 0; row < table.NumberOfRecords(); row++ {
+		ok, err := filter.Matches(table, row)
+		require.Nil(t, err)
+		if ok {
+			matches = append(matches, row)
+		}
+	}
+	require.Equal(t, matches, []int{0})
+	require.Equal(t, filter.String(), "AMOUNT > 100 .AND. !DELETED()")
+
+	_, err := MustParse("AMOUNT + 1").Matches(table, 0)
+	require.EqualError(t, err, "expression AMOUNT + 1 is numeric, not logical")
+	_, err = filter.Evaluate(table, 2)
+	require.EqualError(t, err, "row 2 does not exist")
+}
+
+func TestExpression_EvaluationErrors(t *testing.T) {
+	table := createPeopleTable(t)
+	for source, expected := range map[string]string{
+		"CITY":                "field \"CITY\" at position 0 does not exist",
+		"PHOTO":               "field \"PHOTO\" of type 'P' cannot be used in expressions",
+		"LASTNAME + 1":        "operator + at position 9 cannot be applied to character and numeric values",
+		"BIRTH * 2":           "operator * at position 6 cannot be applied to date and numeric values",
+		"AMOUNT = 'x'":        "operator = at position 7 cannot be applied to numeric and character values",
+		"AMOUNT / 0":          "division by zero at position 7",
+		"-LASTNAME":           "unary - at position 0 expects a numeric value, not character",
+		".NOT. AMOUNT":        ".NOT. expects a logical value, not numeric",
+		"AMOUNT .AND. ACTIVE": ".AND. expects logical values, not numeric",
+		"UPPER(AMOUNT)":       "UPPER() at position 0: argument 1 must be character, not numeric",
+		"LEFT(LASTNAME, -1)":  "LEFT() at position 0: argument 2 must not be negative, not -1",
+		"IIF(AMOUNT, 1, 2)":   "IIF() expects a logical condition, not numeric",
+		"MAX(AMOUNT, BIRTH)":  "MAX() at position 0: cannot compare numeric and date values",
+	} {
+		e, err := Parse(source)
+		require.Nil(t, err, source)
+		_, err = e.Evaluate(table, 0)
+		require.EqualError(t, err, expected, source)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for source, expected := range map[string]string{
+		"":                  "expected value at end of expression",
+		"AMOUNT >":          "expected value at end of expression",
+		"AMOUNT 100":        "expected operator at position 7",
+		"(AMOUNT":           "expected \")\" at end of expression",
+		"AMOUNT .XOR. 1":    "unexpected \".XOR\" at position 7",
+		"'open":             "unterminated ' at position 0",
+		"AMOUNT @ 1":        "unexpected character '@' at position 7",
+		"1.2.3":             "invalid number \"1.2.3\" at position 0",
+		"{^2024-13-01}":     "invalid date {^2024-13-01} at position 0",
+		"NOSUCH(1)":         "unknown function NOSUCH() at position 0",
+		"UPPER(1, 2)":       "UPPER() takes 1 argument, not 2, at position 0",
+		"SUBSTR('x')":       "SUBSTR() takes 2 to 3 arguments, not 1, at position 0",
+		"PEOPLE->":          "expected field name at end of expression",
+		"IIF(.T., 1, 2, 3)": "IIF() takes 3 arguments, not 4, at position 0",
+	} {
+		_, err := Parse(source)
+		require.EqualError(t, err, expected, source)
+	}
+	require.Panics(t, func() { MustParse("(") })
+}
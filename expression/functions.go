@@ -0,0 +1,435 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	defaultStrLength      = 10
+	abbreviatedNameLength = 4        // the fewest letters to which function names may be abbreviated
+	maxStringLength       = 16777184 // the longest string of Visual FoxPro, and so the greatest length or position
+	maxDecimals           = 18       // the most decimal places of Visual FoxPro numbers
+)
+
+// function is a function that can be called by expressions.
+type function struct {
+	name             string
+	minArgs, maxArgs int
+	call             func(c *context, a args) (interface{}, error)
+	// lazy, if set, is called rather than call with the unevaluated arguments, for functions such as IIF() that
+	// evaluate only some of them
+	lazy func(c *context, a []node) (interface{}, error)
+}
+
+// arity describes the number of arguments of the function, for error messages.
+func (f *function) arity() string {
+	switch {
+	case f.minArgs == f.maxArgs && f.minArgs == 1:
+		return "1 argument"
+	case f.minArgs == f.maxArgs:
+		return fmt.Sprintf("%d arguments", f.minArgs)
+	}
+	return fmt.Sprintf("%d to %d arguments", f.minArgs, f.maxArgs)
+}
+
+var functions = map[string]*function{}
+
+func init() {
+	for _, f := range []*function{
+		{name: "UPPER", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			s, err := a.string(0)
+			return strings.ToUpper(s), err
+		}},
+		{name: "LOWER", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			s, err := a.string(0)
+			return strings.ToLower(s), err
+		}},
+		{name: "TRIM", minArgs: 1, maxArgs: 1, call: trimRight},
+		{name: "RTRIM", minArgs: 1, maxArgs: 1, call: trimRight},
+		{name: "LTRIM", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			s, err := a.string(0)
+			return strings.TrimLeft(s, " "), err
+		}},
+		{name: "ALLTRIM", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			s, err := a.string(0)
+			return strings.Trim(s, " "), err
+		}},
+		{name: "SUBSTR", minArgs: 2, maxArgs: 3, call: substr},
+		{name: "LEFT", minArgs: 2, maxArgs: 2, call: func(_ *context, a args) (interface{}, error) {
+			runes, n, err := a.runesAndCount()
+			if err != nil {
+				return nil, err
+			}
+			return string(runes[:n]), nil
+		}},
+		{name: "RIGHT", minArgs: 2, maxArgs: 2, call: func(_ *context, a args) (interface{}, error) {
+			runes, n, err := a.runesAndCount()
+			if err != nil {
+				return nil, err
+			}
+			return string(runes[len(runes)-n:]), nil
+		}},
+		{name: "LEN", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			s, err := a.string(0)
+			return float64(utf8.RuneCountInString(s)), err
+		}},
+		{name: "SPACE", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			n, err := a.count(0)
+			return strings.Repeat(" ", n), err
+		}},
+		{name: "STR", minArgs: 1, maxArgs: 3, call: str},
+		{name: "VAL", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			s, err := a.string(0)
+			return val(s), err
+		}},
+		{name: "DTOS", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			d, err := a.date(0)
+			if d.IsZero() {
+				return strings.Repeat(" ", 8), err
+			}
+			return d.Format("20060102"), err
+		}},
+		{name: "DTOC", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			d, err := a.date(0)
+			if d.IsZero() {
+				return "  /  /    ", err
+			}
+			return d.Format("01/02/2006"), err
+		}},
+		{name: "CTOD", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			s, err := a.string(0)
+			d, _ := parseAmericanDate(s)
+			return d, err
+		}},
+		{name: "YEAR", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			return a.datePart(time.Time.Year)
+		}},
+		{name: "MONTH", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			return a.datePart(func(t time.Time) int { return int(t.Month()) })
+		}},
+		{name: "DAY", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			return a.datePart(time.Time.Day)
+		}},
+		{name: "ABS", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			f, err := a.number(0)
+			return math.Abs(f), err
+		}},
+		{name: "INT", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			f, err := a.number(0)
+			return math.Trunc(f), err
+		}},
+		{name: "ROUND", minArgs: 2, maxArgs: 2, call: func(_ *context, a args) (interface{}, error) {
+			f, err := a.number(0)
+			if err != nil {
+				return nil, err
+			}
+			var decimals int
+			if decimals, err = a.integer(1, -maxDecimals, maxDecimals); err != nil {
+				return nil, err
+			}
+			return round(f, decimals), nil
+		}},
+		{name: "MIN", minArgs: 2, maxArgs: 2, call: func(_ *context, a args) (interface{}, error) {
+			return a.extreme(-1)
+		}},
+		{name: "MAX", minArgs: 2, maxArgs: 2, call: func(_ *context, a args) (interface{}, error) {
+			return a.extreme(1)
+		}},
+		{name: "EMPTY", minArgs: 1, maxArgs: 1, call: func(_ *context, a args) (interface{}, error) {
+			switch v := a[0].(type) {
+			case string:
+				return strings.Trim(v, " \t\r\n") == "", nil
+			case float64:
+				return v == 0, nil
+			case bool:
+				return !v, nil
+			case time.Time:
+				return v.IsZero(), nil
+			}
+			return nil, fmt.Errorf("unexpected %s value", typeName(a[0]))
+		}},
+		{name: "IIF", minArgs: 3, maxArgs: 3, lazy: iif},
+		{name: "DELETED", minArgs: 0, maxArgs: 0, call: func(c *context, _ args) (interface{}, error) {
+			return c.table.RowIsDeleted(c.row), nil
+		}},
+		{name: "RECNO", minArgs: 0, maxArgs: 0, call: func(c *context, _ args) (interface{}, error) {
+			return float64(c.row + 1), nil
+		}},
+	} {
+		functions[f.name] = f
+	}
+}
+
+// lookupFunction returns the function with the given name, regardless of case, which may be abbreviated to its first
+// four letters.
+func lookupFunction(name string) (*function, error) {
+	name = strings.ToUpper(name)
+	if f, found := functions[name]; found {
+		return f, nil
+	}
+
+	var matches []string
+	if utf8.RuneCountInString(name) >= abbreviatedNameLength {
+		for fullName := range functions {
+			if strings.HasPrefix(fullName, name) {
+				matches = append(matches, fullName)
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("unknown function %s()", name)
+	case 1:
+		return functions[matches[0]], nil
+	}
+	sort.Strings(matches)
+	return nil, fmt.Errorf("%s() is ambiguous between %s()", name, strings.Join(matches, "(), "))
+}
+
+func trimRight(_ *context, a args) (interface{}, error) {
+	s, err := a.string(0)
+	return strings.TrimRight(s, " "), err
+}
+
+// substr returns the part of the string from the 1-based start, to its end or of the optional length.
+func substr(_ *context, a args) (interface{}, error) {
+	s, err := a.string(0)
+	if err != nil {
+		return nil, err
+	}
+	var start int
+	if start, err = a.integer(1, -maxStringLength, maxStringLength); err != nil {
+		return nil, err
+	}
+
+	runes := []rune(s)
+	from := start - 1
+	if from < 0 {
+		from = 0
+	}
+	if from >= len(runes) {
+		return "", nil
+	}
+	to := len(runes)
+	if len(a) > 2 {
+		var length int
+		if length, err = a.count(2); err != nil {
+			return nil, err
+		}
+		if from+length < to {
+			to = from + length
+		}
+	}
+	return string(runes[from:to]), nil
+}
+
+// str formats the number right-aligned in a string of the optional length, 10 by default, with the optional number of
+// decimal places, none by default. Decimal places are dropped where the number does not fit otherwise, and a string
+// of asterisks is returned if it does not fit without them.
+func str(_ *context, a args) (interface{}, error) {
+	f, err := a.number(0)
+	if err != nil {
+		return nil, err
+	}
+	length, decimals := defaultStrLength, 0
+	if len(a) > 1 {
+		if length, err = a.count(1); err != nil {
+			return nil, err
+		}
+	}
+	if len(a) > 2 {
+		if decimals, err = a.integer(2, 0, maxDecimals); err != nil {
+			return nil, err
+		}
+	}
+
+	for ; decimals >= 0; decimals-- {
+		if s := strconv.FormatFloat(round(f, decimals), 'f', decimals, 64); len(s) <= length {
+			return padLeft(s, length), nil
+		}
+	}
+	return strings.Repeat("*", length), nil
+}
+
+// val returns the number at the start of the string, after any leading blanks, or 0 if there is none.
+func val(s string) float64 {
+	s = strings.TrimLeft(s, " ")
+	end := 0
+	if end < len(s) && (s[end] == '-' || s[end] == '+') {
+		end++
+	}
+	digits, point := 0, false
+	for ; end < len(s); end++ {
+		switch {
+		case s[end] >= '0' && s[end] <= '9':
+			digits++
+			continue
+		case s[end] == '.' && !point:
+			point = true
+			continue
+		}
+		break
+	}
+	if digits == 0 {
+		return 0
+	}
+	f, _ := strconv.ParseFloat(strings.TrimSuffix(s[:end], "."), 64)
+	return f
+}
+
+// iif evaluates and returns the second argument if the first is true, or else the third.
+func iif(c *context, a []node) (interface{}, error) {
+	value, err := a[0].evaluate(c)
+	if err != nil {
+		return nil, err
+	}
+	condition, ok := value.(bool)
+	if !ok {
+		return nil, fmt.Errorf("IIF() expects a logical condition, not %s", typeName(value))
+	}
+	if condition {
+		return a[1].evaluate(c)
+	}
+	return a[2].evaluate(c)
+}
+
+// round rounds the number half away from zero to the given number of decimal places, which may be negative.
+func round(f float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	if math.IsInf(f*scale, 0) {
+		return f // too great to have such decimal places
+	}
+	return math.Round(f*scale) / scale
+}
+
+// parseAmericanDate parses a date as MM/DD/YYYY or MM/DD/YY, with years of two digits in the 20th century, as per
+// SET DATE AMERICAN and SET EPOCH TO 1900. Dashes and dots may separate the parts instead of slashes.
+func parseAmericanDate(s string) (time.Time, bool) {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '/' || r == '-' || r == '.' || unicode.IsSpace(r)
+	})
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return time.Time{}, false
+		}
+		numbers[i] = n
+	}
+	month, day, year := numbers[0], numbers[1], numbers[2]
+	if len(parts[2]) <= 2 {
+		year += 1900
+	}
+
+	d := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if d.Year() != year || int(d.Month()) != month || d.Day() != day {
+		return time.Time{}, false
+	}
+	return d, true
+}
+
+// padLeft pads the string with leading blanks to the given number of characters.
+func padLeft(s string, length int) string {
+	if n := utf8.RuneCountInString(s); n < length {
+		return strings.Repeat(" ", length-n) + s
+	}
+	return s
+}
+
+// args are the values of the arguments of a function call.
+type args []interface{}
+
+func (a args) string(i int) (string, error) {
+	if s, ok := a[i].(string); ok {
+		return s, nil
+	}
+	return "", a.unexpected(i, "character")
+}
+
+func (a args) number(i int) (float64, error) {
+	if f, ok := a[i].(float64); ok {
+		return f, nil
+	}
+	return 0, a.unexpected(i, "numeric")
+}
+
+// count returns the argument as a non-negative integer, such as a length.
+func (a args) count(i int) (int, error) {
+	return a.integer(i, 0, maxStringLength)
+}
+
+// integer returns the integer part of the argument, which must be from min to max.
+func (a args) integer(i int, min, max int) (int, error) {
+	f, err := a.number(i)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case f < 0 && min == 0:
+		return 0, fmt.Errorf("argument %d must not be negative, not %s", i+1, formatNumber(f))
+	case !(f >= float64(min) && f <= float64(max)):
+		return 0, fmt.Errorf("argument %d must be from %d to %d, not %s", i+1, min, max, formatNumber(f))
+	}
+	return int(f), nil
+}
+
+func (a args) date(i int) (time.Time, error) {
+	if t, ok := a[i].(time.Time); ok {
+		return t, nil
+	}
+	return time.Time{}, a.unexpected(i, "date")
+}
+
+// datePart returns a part of the date of the first argument, or 0 for the empty date.
+func (a args) datePart(part func(time.Time) int) (interface{}, error) {
+	d, err := a.date(0)
+	if err != nil || d.IsZero() {
+		return 0.0, err
+	}
+	return float64(part(d)), nil
+}
+
+// runesAndCount returns the characters of the string of the first argument, and the count of the second argument
+// limited to their number.
+func (a args) runesAndCount() ([]rune, int, error) {
+	s, err := a.string(0)
+	if err != nil {
+		return nil, 0, err
+	}
+	var n int
+	if n, err = a.count(1); err != nil {
+		return nil, 0, err
+	}
+	runes := []rune(s)
+	if n > len(runes) {
+		n = len(runes)
+	}
+	return runes, n, nil
+}
+
+// extreme returns the lesser of the two arguments if sign is -1, or the greater if sign is 1.
+func (a args) extreme(sign int) (interface{}, error) {
+	order, _, ok := compare(a[0], a[1], true)
+	if !ok {
+		return nil, fmt.Errorf("cannot compare %s and %s values", typeName(a[0]), typeName(a[1]))
+	}
+	if order*sign >= 0 {
+		return a[0], nil
+	}
+	return a[1], nil
+}
+
+func (a args) unexpected(i int, expected string) error {
+	return fmt.Errorf("argument %d must be %s, not %s", i+1, expected, typeName(a[i]))
+}
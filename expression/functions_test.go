@@ -0,0 +1,128 @@
+package expression
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NovikovRoman/godbf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunctions(t *testing.T) {
+	table := godbf.New(nil)
+	require.Nil(t, table.AddTextField("NAME", 5))
+	_, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	for source, expected := range map[string]interface{}{
+		"LOWER('AbC')":                  "abc",
+		"TRIM('  a  ') + '|'":           "  a|",
+		"RTRIM('a  ') + '|'":            "a|",
+		"LTRIM('  a  ') + '|'":          "a  |",
+		"ALLTRIM('  a  ') + '|'":        "a|",
+		"SUBSTR('привет', 2, 3)":        "рив",
+		"SUBSTR('hello', 3)":            "llo",
+		"SUBSTR('hello', 9)":            "",
+		"SUBSTR('hello', 4, 10)":        "lo",
+		"subs('hello', 2, 2)":           "el",
+		"LEFT('hello', 2)":              "he",
+		"RIGHT('hello', 3)":             "llo",
+		"LEFT('hi', 5)":                 "hi",
+		"LEN(NAME)":                     5.0,
+		"LEN(SPACE(3))":                 3.0,
+		"STR(42)":                       "        42",
+		"STR(-3.14159, 6, 2)":           " -3.14",
+		"STR(2.5, 3)":                   "  3",
+		"STR(123.456, 5, 2)":            "123.5",
+		"STR(123456, 5)":                "*****",
+		"VAL('  12.5abc')":              12.5,
+		"VAL('-7.')":                    -7.0,
+		"VAL('abc')":                    0.0,
+		"DTOS({^2024-02-29})":           "20240229",
+		"DTOC({^2024-02-29})":           "02/29/2024",
+		"DTOC({})":                      "  /  /    ",
+		"DTOS(CTOD('1/2/99'))":          "19990102",
+		"CTOD('02/30/2024')":            time.Time{},
+		"CTOD('')":                      time.Time{},
+		"YEAR({^2024-02-29})":           2024.0,
+		"MONTH({^2024-02-29})":          2.0,
+		"DAY({^2024-02-29})":            29.0,
+		"YEAR({})":                      0.0,
+		"ABS(-2)":                       2.0,
+		"INT(-2.7)":                     -2.0,
+		"ROUND(2.345, 2)":               2.35,
+		"ROUND(1250, -2)":               1300.0,
+		"MIN(3, 2)":                     2.0,
+		"MAX('a', 'b')":                 "b",
+		"MAX({^2024-01-01}, {})":        time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"EMPTY(NAME)":                   true,
+		"EMPTY(0) .AND. EMPTY(.F.)":     true,
+		"EMPTY('x')":                    false,
+		"IIF(.F., 1/0, 'no')":           "no",
+		"-7 % 3":                        2.0,
+		"7 % -3":                        -2.0,
+		"ALLT(' x ') + UPPE('y')":       "xY",
+		"IIF(DELE(), 'gone', 'here')":   "here",
+		"RECN() + RECNO()":              2.0,
+		"DTOS({^2024-01-31} + 1)":       "20240201",
+		"DTOS({^2024-03-01} - 1)":       "20240229",
+		"{^2024-03-01} - {^2024-02-01}": 29.0,
+	} {
+		e, err := Parse(source)
+		require.Nil(t, err, source)
+		value, err := e.Evaluate(table, 0)
+		require.Nil(t, err, source)
+		require.Equal(t, value, expected, source)
+	}
+}
+
+func TestFunctions_OutOfRangeArguments(t *testing.T) {
+	table := godbf.New(nil)
+	require.Nil(t, table.AddTextField("NAME", 5))
+	_, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	for source, expected := range map[string]string{
+		"SUBSTR('abc', 1, 100000000000000000000)": "SUBSTR() at position 0: argument 3 must be from 0 to 16777184, not 100000000000000000000",
+		"SUBSTR('abc', -100000000000000000000)":   "SUBSTR() at position 0: argument 2 must be from -16777184 to 16777184, not -100000000000000000000",
+		"LEFT('abc', 100000000000000000000)":      "LEFT() at position 0: argument 2 must be from 0 to 16777184, not 100000000000000000000",
+		"RIGHT('abc', 100000000000000000000)":     "RIGHT() at position 0: argument 2 must be from 0 to 16777184, not 100000000000000000000",
+		"SPACE(100000000000000000000)":            "SPACE() at position 0: argument 1 must be from 0 to 16777184, not 100000000000000000000",
+		"STR(1, 100000000000000000000)":           "STR() at position 0: argument 2 must be from 0 to 16777184, not 100000000000000000000",
+		"STR(1, 10, 400)":                         "STR() at position 0: argument 3 must be from 0 to 18, not 400",
+		"ROUND(1.5, 100000000000000000000)":       "ROUND() at position 0: argument 2 must be from -18 to 18, not 100000000000000000000",
+		"ROUND(1.5, -19)":                         "ROUND() at position 0: argument 2 must be from -18 to 18, not -19",
+		"LEFT('abc', (-1)^0.5)":                   "LEFT() at position 0: argument 2 must be from 0 to 16777184, not NaN",
+	} {
+		e, err := Parse(source)
+		require.Nil(t, err, source)
+		_, err = e.Evaluate(table, 0)
+		require.EqualError(t, err, expected, source)
+	}
+
+	for source, expected := range map[string]interface{}{
+		"SUBSTR('abc', 2, 16777184)":        "bc",
+		"STR(1, 22, 18)":                    "  1.000000000000000000",
+		"ROUND(10^300, 18) = 10^300":        true,
+		"ROUND(123.456, 18)":                123.456,
+		"ROUND(150000000000000000000, -18)": 1.5e20,
+		"LEN(SPACE(1000))":                  1000.0,
+	} {
+		value, err := MustParse(source).Evaluate(table, 0)
+		require.Nil(t, err, source)
+		require.Equal(t, value, expected, source)
+	}
+}
+
+func TestLookupFunction(t *testing.T) {
+	f, err := lookupFunction("substr")
+	require.Nil(t, err)
+	require.Equal(t, f.name, "SUBSTR")
+
+	f, err = lookupFunction("DELET")
+	require.Nil(t, err)
+	require.Equal(t, f.name, "DELETED")
+
+	_, err = lookupFunction("SUB")
+	require.EqualError(t, err, "unknown function SUB()")
+}
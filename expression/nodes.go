@@ -0,0 +1,392 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NovikovRoman/godbf"
+)
+
+// node is a node of the tree of a parsed expression.
+type node interface {
+	evaluate(c *context) (interface{}, error)
+}
+
+type literalNode struct {
+	value interface{}
+}
+
+func (n *literalNode) evaluate(*context) (interface{}, error) {
+	return n.value, nil
+}
+
+type fieldNode struct {
+	name string
+	pos  int
+}
+
+func (n *fieldNode) evaluate(c *context) (interface{}, error) {
+	i, found := c.fieldIndex(n.name)
+	if !found {
+		return nil, fmt.Errorf("field \"%s\" at position %d does not exist", n.name, n.pos)
+	}
+	return fieldValue(c.table, c.row, i)
+}
+
+// fieldValue returns the value of the field with the given index, for the record at the given row, as per the type of
+// the field.
+func fieldValue(table *godbf.DbfTable, row int, fieldIndex int) (interface{}, error) {
+	field := table.Fields()[fieldIndex]
+	isNull := table.FieldIsNull(row, fieldIndex)
+
+	switch field.FieldType() {
+	case godbf.Character:
+		value := ""
+		if !isNull {
+			value = table.FieldValue(row, fieldIndex)
+		}
+		return padRight(value, int(field.Length())), nil
+	case godbf.Memo:
+		if isNull {
+			return "", nil
+		}
+		return table.MemoFieldValue(row, fieldIndex)
+	case godbf.Numeric, godbf.Float, godbf.Integer, godbf.AutoIncrement, godbf.Currency, godbf.Double, godbf.Double7:
+		value, err := table.TypedFieldValue(row, fieldIndex)
+		switch v := value.(type) {
+		case int64:
+			return float64(v), err
+		case float64:
+			return v, err
+		}
+		return 0.0, err
+	case godbf.Logical:
+		value, _, err := table.BoolFieldValue(row, fieldIndex)
+		return value, err
+	case godbf.Date, godbf.DateTime, godbf.Timestamp:
+		value, err := table.TypedFieldValue(row, fieldIndex)
+		t, _ := value.(time.Time)
+		if !t.IsZero() && field.FieldType() == godbf.Date {
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		}
+		return t, err
+	}
+	return nil, fmt.Errorf("field \"%s\" of type '%c' cannot be used in expressions", field.Name(), field.FieldType())
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) evaluate(c *context) (interface{}, error) {
+	value, err := n.operand.evaluate(c)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return nil, fmt.Errorf(".NOT. expects a logical value, not %s", typeName(value))
+	}
+	return !b, nil
+}
+
+// logicalNode is .AND. or .OR., of which the right operand is only evaluated if it decides the result.
+type logicalNode struct {
+	or          bool
+	left, right node
+}
+
+func (n *logicalNode) evaluate(c *context) (interface{}, error) {
+	operator := ".AND."
+	if n.or {
+		operator = ".OR."
+	}
+
+	for i, operand := range []node{n.left, n.right} {
+		value, err := operand.evaluate(c)
+		if err != nil {
+			return nil, err
+		}
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s expects logical values, not %s", operator, typeName(value))
+		}
+		if b == n.or || i == 1 {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
+
+type negateNode struct {
+	operand node
+	pos     int
+}
+
+func (n *negateNode) evaluate(c *context) (interface{}, error) {
+	value, err := n.operand.evaluate(c)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("unary - at position %d expects a numeric value, not %s", n.pos, typeName(value))
+	}
+	return -f, nil
+}
+
+type binaryNode struct {
+	operator    string
+	left, right node
+	pos         int
+}
+
+func (n *binaryNode) evaluate(c *context) (interface{}, error) {
+	left, err := n.left.evaluate(c)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.evaluate(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var result interface{}
+	switch n.operator {
+	case "+":
+		result = add(left, right)
+	case "-":
+		result = subtract(left, right)
+	case "*", "/", "%", "^":
+		x, xOK := left.(float64)
+		y, yOK := right.(float64)
+		if xOK && yOK {
+			return arithmetic(n.operator, x, y, n.pos)
+		}
+	case "$":
+		x, xOK := left.(string)
+		y, yOK := right.(string)
+		if xOK && yOK {
+			result = strings.Contains(y, x)
+		}
+	default:
+		var order int
+		var equal, ok bool
+		if order, equal, ok = compare(left, right, n.operator == "=="); ok {
+			result = relation(n.operator, order, equal)
+		}
+	}
+
+	if result == nil {
+		return nil, fmt.Errorf("operator %s at position %d cannot be applied to %s and %s values",
+			n.operator, n.pos, typeName(left), typeName(right))
+	}
+	return result, nil
+}
+
+// add adds numbers, concatenates strings, and adds days to dates. Nil is returned for other values.
+func add(left, right interface{}) interface{} {
+	switch x := left.(type) {
+	case float64:
+		switch y := right.(type) {
+		case float64:
+			return x + y
+		case time.Time:
+			return addDays(y, x)
+		}
+	case string:
+		if y, ok := right.(string); ok {
+			return x + y
+		}
+	case time.Time:
+		if y, ok := right.(float64); ok {
+			return addDays(x, y)
+		}
+	}
+	return nil
+}
+
+// subtract subtracts numbers, concatenates strings with the trailing blanks of the first moved to the end, subtracts
+// days from dates, and returns the number of days between dates. Nil is returned for other values.
+func subtract(left, right interface{}) interface{} {
+	switch x := left.(type) {
+	case float64:
+		if y, ok := right.(float64); ok {
+			return x - y
+		}
+	case string:
+		if y, ok := right.(string); ok {
+			trimmed := strings.TrimRight(x, " ")
+			return trimmed + y + x[len(trimmed):]
+		}
+	case time.Time:
+		switch y := right.(type) {
+		case float64:
+			return addDays(x, -y)
+		case time.Time:
+			if x.IsZero() || y.IsZero() {
+				return 0.0
+			}
+			return x.Sub(y).Hours() / 24
+		}
+	}
+	return nil
+}
+
+// addDays adds a number of days to a date. The empty date stays empty.
+func addDays(t time.Time, days float64) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.Add(time.Duration(days * 24 * float64(time.Hour)))
+}
+
+func arithmetic(operator string, x, y float64, pos int) (interface{}, error) {
+	switch operator {
+	case "*":
+		return x * y, nil
+	case "^":
+		return math.Pow(x, y), nil
+	}
+
+	if y == 0 {
+		return nil, fmt.Errorf("division by zero at position %d", pos)
+	}
+	if operator == "/" {
+		return x / y, nil
+	}
+	// as in xBase, the remainder has the sign of the divisor
+	m := math.Mod(x, y)
+	if m != 0 && (m < 0) != (y < 0) {
+		m += y
+	}
+	return m, nil
+}
+
+// compare returns the order of two values of the same type, and whether they are equal. Strings are equal if the
+// first starts with the second, as xBase compares them with SET EXACT OFF, unless exact is true. ok is false if the
+// values cannot be compared.
+func compare(left, right interface{}, exact bool) (order int, equal bool, ok bool) {
+	switch x := left.(type) {
+	case float64:
+		if y, yOK := right.(float64); yOK {
+			order = compareFloats(x, y)
+			return order, order == 0, true
+		}
+	case string:
+		if y, yOK := right.(string); yOK {
+			equal = x == y
+			if !exact {
+				equal = strings.HasPrefix(x, y)
+			}
+			return strings.Compare(x, y), equal, true
+		}
+	case bool:
+		if y, yOK := right.(bool); yOK {
+			order = compareFloats(boolRank(x), boolRank(y))
+			return order, order == 0, true
+		}
+	case time.Time:
+		if y, yOK := right.(time.Time); yOK {
+			switch {
+			case x.Before(y):
+				order = -1
+			case x.After(y):
+				order = 1
+			}
+			return order, order == 0, true
+		}
+	}
+	return 0, false, false
+}
+
+// relation returns the result of a relational operator, for values of the given order and equality.
+func relation(operator string, order int, equal bool) bool {
+	switch operator {
+	case "=", "==":
+		return equal
+	case "<>", "#", "!=":
+		return !equal
+	case "<":
+		return order < 0 && !equal
+	case "<=":
+		return order < 0 || equal
+	case ">":
+		return order > 0 && !equal
+	}
+	return order > 0 || equal // >=
+}
+
+func compareFloats(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func boolRank(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+type callNode struct {
+	function *function
+	args     []node
+	pos      int
+}
+
+func (n *callNode) evaluate(c *context) (interface{}, error) {
+	if n.function.lazy != nil {
+		return n.function.lazy(c, n.args)
+	}
+
+	values := make([]interface{}, len(n.args))
+	for i, arg := range n.args {
+		var err error
+		if values[i], err = arg.evaluate(c); err != nil {
+			return nil, err
+		}
+	}
+	value, err := n.function.call(c, args(values))
+	if err != nil {
+		return nil, fmt.Errorf("%s() at position %d: %v", n.function.name, n.pos, err)
+	}
+	return value, nil
+}
+
+// typeName returns the xBase name of the type of a value.
+func typeName(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "character"
+	case float64:
+		return "numeric"
+	case bool:
+		return "logical"
+	case time.Time:
+		return "date"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// padRight pads the string with blanks to the given number of characters.
+func padRight(s string, length int) string {
+	if n := utf8.RuneCountInString(s); n < length {
+		return s + strings.Repeat(" ", length-n)
+	}
+	return s
+}
+
+// formatNumber formats a number for error messages.
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
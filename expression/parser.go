@@ -0,0 +1,382 @@
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// tokenKind is the kind of a token of an expression.
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	identifierToken
+	numberToken
+	stringToken
+	dateToken
+	logicalToken
+	operatorToken
+)
+
+type token struct {
+	kind tokenKind
+	text string // the text of the token, the content of strings and dates, or the upper-cased dotted operators
+	pos  int
+}
+
+// dottedWords are the operators and logical literals written between dots, as in .AND. and .T.
+var dottedWords = map[string]tokenKind{
+	"AND": operatorToken, "OR": operatorToken, "NOT": operatorToken,
+	"T": logicalToken, "F": logicalToken, "Y": logicalToken, "N": logicalToken,
+}
+
+// operators are the operators and punctuation of expressions, longest first.
+var operators = []string{"**", "==", "<>", "!=", "<=", ">=", "->",
+	"+", "-", "*", "/", "%", "^", "=", "#", "<", ">", "$", "!", "(", ")", ","}
+
+// tokenize splits an expression into tokens, ending with an endToken.
+func tokenize(source string) (tokens []token, err error) {
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{identifierToken, string(runes[start:i]), start})
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{numberToken, string(runes[start:i]), start})
+		case r == '.':
+			end := i + 1
+			for end < len(runes) && unicode.IsLetter(runes[end]) {
+				end++
+			}
+			word := strings.ToUpper(string(runes[i+1 : end]))
+			kind, known := dottedWords[word]
+			if !known || end >= len(runes) || runes[end] != '.' {
+				return nil, fmt.Errorf("unexpected \".%s\" at position %d", string(runes[i+1:end]), start)
+			}
+			i = end + 1
+			tokens = append(tokens, token{kind, word, start})
+		case r == '\'' || r == '"' || r == '[' || r == '{':
+			closing := r
+			switch r {
+			case '[':
+				closing = ']'
+			case '{':
+				closing = '}'
+			}
+			end := i + 1
+			for end < len(runes) && runes[end] != closing {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated %c at position %d", r, start)
+			}
+			kind := stringToken
+			if r == '{' {
+				kind = dateToken
+			}
+			tokens = append(tokens, token{kind, string(runes[i+1 : end]), start})
+			i = end + 1
+		default:
+			operator := ""
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					operator = op
+					break
+				}
+			}
+			if operator == "" {
+				return nil, fmt.Errorf("unexpected character '%c' at position %d", r, start)
+			}
+			i += len([]rune(operator))
+			tokens = append(tokens, token{operatorToken, operator, start})
+		}
+	}
+	return append(tokens, token{kind: endToken, pos: len(runes)}), nil
+}
+
+// parser parses the tokens of an expression into a tree of nodes, by recursive descent. From lowest to highest, the
+// precedence of the operators is: .OR.; .AND.; .NOT. and !; the relational operators; + and -; *, / and %; ** and ^;
+// and unary + and -.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) parseOr() (n node, err error) {
+	if n, err = p.parseAnd(); err != nil {
+		return
+	}
+	for p.acceptOperator("OR") {
+		var right node
+		if right, err = p.parseAnd(); err != nil {
+			return
+		}
+		n = &logicalNode{or: true, left: n, right: right}
+	}
+	return
+}
+
+func (p *parser) parseAnd() (n node, err error) {
+	if n, err = p.parseNot(); err != nil {
+		return
+	}
+	for p.acceptOperator("AND") {
+		var right node
+		if right, err = p.parseNot(); err != nil {
+			return
+		}
+		n = &logicalNode{left: n, right: right}
+	}
+	return
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.acceptOperator("NOT") || p.acceptOperator("!") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+	return p.parseRelational()
+}
+
+func (p *parser) parseRelational() (n node, err error) {
+	if n, err = p.parseAdditive(); err != nil {
+		return
+	}
+	for {
+		t := p.peek()
+		if t.kind != operatorToken {
+			return
+		}
+		switch t.text {
+		case "=", "==", "<>", "#", "!=", "<", "<=", ">", ">=", "$":
+		default:
+			return
+		}
+		p.pos++
+
+		var right node
+		if right, err = p.parseAdditive(); err != nil {
+			return
+		}
+		n = &binaryNode{operator: t.text, left: n, right: right, pos: t.pos}
+	}
+}
+
+func (p *parser) parseAdditive() (n node, err error) {
+	if n, err = p.parseMultiplicative(); err != nil {
+		return
+	}
+	for {
+		t := p.peek()
+		if t.kind != operatorToken || (t.text != "+" && t.text != "-") {
+			return
+		}
+		p.pos++
+
+		var right node
+		if right, err = p.parseMultiplicative(); err != nil {
+			return
+		}
+		n = &binaryNode{operator: t.text, left: n, right: right, pos: t.pos}
+	}
+}
+
+func (p *parser) parseMultiplicative() (n node, err error) {
+	if n, err = p.parsePower(); err != nil {
+		return
+	}
+	for {
+		t := p.peek()
+		if t.kind != operatorToken || (t.text != "*" && t.text != "/" && t.text != "%") {
+			return
+		}
+		p.pos++
+
+		var right node
+		if right, err = p.parsePower(); err != nil {
+			return
+		}
+		n = &binaryNode{operator: t.text, left: n, right: right, pos: t.pos}
+	}
+}
+
+func (p *parser) parsePower() (n node, err error) {
+	if n, err = p.parseUnary(); err != nil {
+		return
+	}
+	for {
+		t := p.peek()
+		if t.kind != operatorToken || (t.text != "**" && t.text != "^") {
+			return
+		}
+		p.pos++
+
+		var right node
+		if right, err = p.parseUnary(); err != nil {
+			return
+		}
+		n = &binaryNode{operator: "^", left: n, right: right, pos: t.pos}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	t := p.peek()
+	if t.kind == operatorToken && (t.text == "-" || t.text == "+") {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if t.text == "+" {
+			return operand, nil
+		}
+		return &negateNode{operand: operand, pos: t.pos}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a literal, a field, a function call or a parenthesised expression.
+func (p *parser) parsePrimary() (node, error) {
+	t := p.peek()
+	switch t.kind {
+	case numberToken:
+		p.pos++
+		f, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number \"%s\" at position %d", t.text, t.pos)
+		}
+		return &literalNode{value: f}, nil
+	case stringToken:
+		p.pos++
+		return &literalNode{value: t.text}, nil
+	case logicalToken:
+		p.pos++
+		return &literalNode{value: t.text == "T" || t.text == "Y"}, nil
+	case dateToken:
+		p.pos++
+		d, err := parseDateLiteral(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("%v at position %d", err, t.pos)
+		}
+		return &literalNode{value: d}, nil
+	case identifierToken:
+		p.pos++
+		if p.acceptOperator("(") {
+			return p.parseCall(t)
+		}
+		if p.acceptOperator("->") {
+			// the alias of the table is ignored, as expressions are evaluated against a single table
+			field := p.peek()
+			if field.kind != identifierToken {
+				return nil, p.unexpected("field name")
+			}
+			p.pos++
+			return &fieldNode{name: field.text, pos: field.pos}, nil
+		}
+		return &fieldNode{name: t.text, pos: t.pos}, nil
+	case operatorToken:
+		if t.text == "(" {
+			p.pos++
+			n, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return n, p.expectOperator(")")
+		}
+	}
+	return nil, p.unexpected("value")
+}
+
+// parseCall parses the arguments of a call of the function named by the token given, up to the closing parenthesis.
+func (p *parser) parseCall(name token) (node, error) {
+	f, err := lookupFunction(name.text)
+	if err != nil {
+		return nil, fmt.Errorf("%v at position %d", err, name.pos)
+	}
+
+	call := &callNode{function: f, pos: name.pos}
+	if !p.acceptOperator(")") {
+		for {
+			var arg node
+			if arg, err = p.parseOr(); err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+			if !p.acceptOperator(",") {
+				break
+			}
+		}
+		if err = p.expectOperator(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(call.args) < f.minArgs || len(call.args) > f.maxArgs {
+		return nil, fmt.Errorf("%s() takes %s, not %d, at position %d", f.name, f.arity(), len(call.args), name.pos)
+	}
+	return call, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) acceptOperator(operator string) bool {
+	if t := p.peek(); t.kind == operatorToken && t.text == operator {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectOperator(operator string) error {
+	if !p.acceptOperator(operator) {
+		return p.unexpected("\"" + operator + "\"")
+	}
+	return nil
+}
+
+// unexpected returns an error describing the current token, and what was expected in its place.
+func (p *parser) unexpected(expected string) error {
+	t := p.peek()
+	if t.kind == endToken {
+		return fmt.Errorf("expected %s at end of expression", expected)
+	}
+	return fmt.Errorf("expected %s at position %d", expected, t.pos)
+}
+
+// parseDateLiteral parses the content of a date literal, such as {^2024-01-31} in the strict format of Visual FoxPro,
+// {01/31/2024} in the American format of dBase, or {} for an empty date.
+func parseDateLiteral(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || text == "/  /" {
+		return time.Time{}, nil
+	}
+	if strings.HasPrefix(text, "^") {
+		if d, err := time.Parse("2006-01-02", strings.TrimSpace(text[1:])); err == nil {
+			return d, nil
+		}
+		return time.Time{}, fmt.Errorf("invalid date {%s}", text)
+	}
+	if d, ok := parseAmericanDate(text); ok {
+		return d, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date {%s}", text)
+}
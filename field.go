@@ -1,14 +1,29 @@
 package godbf
 
+import (
+	"fmt"
+	"strings"
+)
+
 // FieldDescriptor describes one field/column in a DbfTable as per https://www.dbase.com/Knowledgebase/INT/db7_file_fmt.htm, Heading 1.2.
 type FieldDescriptor struct {
 	name          string
 	fieldType     DbaseDataType
 	length        byte
-	decimalPlaces byte // Field decimal count in binary
-	fieldStore    [32]byte
+	decimalPlaces byte   // Field decimal count in binary
+	flags         byte   // Visual FoxPro field flags
+	raw           bool   // the field type is not understood, values are only available as raw bytes
+	fieldStore    []byte // the field descriptor as stored in the header
 }
 
+// Visual FoxPro field flags, as stored in byte 18 of a field descriptor.
+const (
+	systemFieldFlag        byte = 0x01
+	nullableFieldFlag      byte = 0x02
+	binaryFieldFlag        byte = 0x04
+	autoIncrementFieldFlag byte = 0x08
+)
+
 // Name returns the column name of the field
 func (fd *FieldDescriptor) Name() string {
 	return fd.name
@@ -29,6 +44,37 @@ func (fd *FieldDescriptor) DecimalPlaces() byte {
 	return fd.decimalPlaces
 }
 
+// IsRaw returns true if the type of the field is not understood. Its values can still be read and written as raw bytes
+// through RawFieldValue() and SetRawFieldValue(), and are carried through unchanged otherwise.
+func (fd *FieldDescriptor) IsRaw() bool {
+	return fd.raw
+}
+
+// IsSystem returns true if the field is a Visual FoxPro system field, hidden from users (such as _NullFlags).
+func (fd *FieldDescriptor) IsSystem() bool {
+	return fd.flags&systemFieldFlag != 0
+}
+
+// IsNullable returns true if the field is a Visual FoxPro field that can store NULL values.
+func (fd *FieldDescriptor) IsNullable() bool {
+	return fd.flags&nullableFieldFlag != 0
+}
+
+// IsBinary returns true if the field is a Visual FoxPro field holding binary data, exempt from code page translation.
+func (fd *FieldDescriptor) IsBinary() bool {
+	return fd.flags&binaryFieldFlag != 0
+}
+
+// IsAutoIncrement returns true if the field is a Visual FoxPro field whose values are incremented automatically.
+func (fd *FieldDescriptor) IsAutoIncrement() bool {
+	return fd.flags&autoIncrementFieldFlag != 0
+}
+
+// holdsBinaryData returns true if the field's values must be passed on without any character decoding or encoding.
+func (fd *FieldDescriptor) holdsBinaryData() bool {
+	return fd.fieldType.isBinaryMemo() || fd.IsBinary()
+}
+
 func (fd FieldDescriptor) usesDecimalPlaces() bool {
 	return fd.fieldType.usesDecimalCount()
 }
@@ -42,6 +88,23 @@ const (
 	Date      DbaseDataType = 'D'
 	Numeric   DbaseDataType = 'N'
 	Float     DbaseDataType = 'F'
+	Memo      DbaseDataType = 'M'
+	General   DbaseDataType = 'G' // FoxPro OLE object, kept in the memo file
+	Picture   DbaseDataType = 'P' // FoxPro picture, kept in the memo file
+
+	// Visual FoxPro binary types
+	Integer  DbaseDataType = 'I' // 4-byte little-endian signed integer
+	Currency DbaseDataType = 'Y' // 8-byte little-endian signed integer, scaled by 10000
+	DateTime DbaseDataType = 'T' // 4-byte little-endian Julian day number, then 4-byte milliseconds since midnight
+	Double   DbaseDataType = 'B' // 8-byte little-endian IEEE 754 floating point number
+
+	// dBase 7 binary types; dBase 7 also stores Integer fields differently to Visual FoxPro, see binary_types.go
+	AutoIncrement DbaseDataType = '+' // as per Integer, incremented automatically for each new record
+	Timestamp     DbaseDataType = '@' // 4-byte Julian day number, then 4-byte milliseconds since midnight
+	Double7       DbaseDataType = 'O' // 8-byte IEEE 754 floating point number, stored to sort bytewise
+
+	// Visual FoxPro system field type, see null.go
+	NullFlags DbaseDataType = '0' // bit field flagging the NULL values of nullable fields, kept as a raw field
 )
 
 func (ddt DbaseDataType) byte() byte {
@@ -57,6 +120,12 @@ func (ddt DbaseDataType) fixedFieldLength() byte {
 		return 1
 	case Date:
 		return 8
+	case Memo, General, Picture:
+		return memoBlockPointerLength
+	case Integer, AutoIncrement:
+		return 4
+	case Currency, DateTime, Double, Timestamp, Double7:
+		return 8
 	default:
 		return notApplicable
 	}
@@ -65,13 +134,58 @@ func (ddt DbaseDataType) fixedFieldLength() byte {
 // usesDecimalCount indicates whether the data type describes a field that makes use of a field's decimal count setting.
 func (ddt DbaseDataType) usesDecimalCount() bool {
 	switch ddt {
-	case Float, Numeric:
+	case Float, Numeric, Double:
 		return true
 	default:
 		return false
 	}
 }
 
+// oneOf returns true if the data type is any of the data types given.
+func (ddt DbaseDataType) oneOf(dataTypes ...DbaseDataType) bool {
+	for _, dataType := range dataTypes {
+		if ddt == dataType {
+			return true
+		}
+	}
+	return false
+}
+
+// joinDataTypes lists the data types given for use in error messages, for example "'I' or '+'".
+func joinDataTypes(dataTypes []DbaseDataType) string {
+	s := make([]string, len(dataTypes))
+	for i, dataType := range dataTypes {
+		s[i] = fmt.Sprintf("'%c'", dataType)
+	}
+	return strings.Join(s, " or ")
+}
+
+// isBinary indicates whether the data type describes a field whose values are stored in a binary, rather than a
+// textual, representation.
+func (ddt DbaseDataType) isBinary() bool {
+	switch ddt {
+	case Integer, Currency, DateTime, Double, AutoIncrement, Timestamp, Double7:
+		return true
+	default:
+		return false
+	}
+}
+
+// usesMemo indicates whether the data type describes a field whose values are kept in a separate memo file.
+func (ddt DbaseDataType) usesMemo() bool {
+	switch ddt {
+	case Memo, General, Picture:
+		return true
+	default:
+		return false
+	}
+}
+
+// isBinaryMemo indicates whether the data type describes a memo field holding binary, rather than text, values.
+func (ddt DbaseDataType) isBinaryMemo() bool {
+	return ddt == General || ddt == Picture
+}
+
 // decimalCountNotApplicable is a convenience decorator supplying a 0-valued byte. THis is used indicate that the data
 // type describes a field that does not make use its decimal count setting.
 func (ddt DbaseDataType) decimalCountNotApplicable() byte {
@@ -0,0 +1,263 @@
+package godbf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Format identifies the xBase dialect a table is encoded in. It determines the file signature, and the layout of
+// any companion memo file.
+type Format int
+
+const (
+	// DBaseIII is the dBase III PLUS format, the default for new tables. Memo values are kept in a .DBT file of
+	// 512-byte blocks, each value being terminated by end-of-file markers.
+	DBaseIII Format = iota
+	// DBaseIV is the dBase IV format. Memo values are kept in a .DBT file where each value is prefixed by its length.
+	DBaseIV
+	// FoxPro is the FoxPro 2.x format. Memo, General and Picture values are kept in a .FPT file, where each value is
+	// prefixed by its type and length.
+	FoxPro
+	// VisualFoxPro is the Visual FoxPro format. Memo values are kept in a .FPT file, as for FoxPro, but memo fields
+	// hold 4-byte binary block pointers. The header ends with a 263-byte backlink to the table's database container.
+	VisualFoxPro
+	// DBase7 is the dBase 7 (Level 7) format. The header holds the name of the language driver, and field descriptors
+	// are 48 bytes long, allowing field names of up to 31 characters. Memo values are kept as for DBaseIV.
+	DBase7
+)
+
+const (
+	dBase7HeaderPrefixLength          = 68
+	dBase7LanguageDriverNameIndex     = 32
+	dBase7LanguageDriverNameLength    = 32
+	dBase7FieldDescriptorLength       = 48
+	dBase7FieldNameByteLength         = 32
+	dBase7AutoIncrementNextValueIndex = 40
+
+	vfpBacklinkLength   = 263
+	vfpTableHasMemoFlag = 0x02
+)
+
+// TableOption configures a new DbfTable created via New().
+type TableOption func(dt *DbfTable)
+
+// WithFormat creates the table in the xBase dialect given, rather than the default of DBaseIII.
+func WithFormat(format Format) TableOption {
+	return func(dt *DbfTable) {
+		dt.format = format
+	}
+}
+
+// WithLocation has dates of the table returned in, and its date of last update interpreted in, the location given,
+// rather than the default of time.Local. See SetLocation().
+func WithLocation(location *time.Location) TableOption {
+	return func(dt *DbfTable) {
+		dt.SetLocation(location)
+	}
+}
+
+// Format returns the xBase dialect the table is encoded in.
+func (dt *DbfTable) Format() Format {
+	return dt.format
+}
+
+// LanguageDriverName returns the name of the language driver stored in the header of dBase 7 tables, such as
+// "DBWINUS0". An empty string is returned for other formats.
+func (dt *DbfTable) LanguageDriverName() string {
+	if dt.format != DBase7 {
+		return ""
+	}
+
+	b := dt.dataStore[dBase7LanguageDriverNameIndex : dBase7LanguageDriverNameIndex+dBase7LanguageDriverNameLength]
+	if end := bytes.IndexByte(b, null); end >= 0 {
+		b = b[:end]
+	}
+	return string(b)
+}
+
+// SetLanguageDriverName sets the name of the language driver stored in the header of dBase 7 tables.
+// An error is returned for other formats, or if the name is longer than 31 bytes.
+func (dt *DbfTable) SetLanguageDriverName(name string) error {
+	if dt.format != DBase7 {
+		return errors.New("only dBase 7 tables have a language driver name")
+	}
+	if len(name) >= dBase7LanguageDriverNameLength {
+		return fmt.Errorf("language driver name of %d bytes exceeds the %d bytes available",
+			len(name), dBase7LanguageDriverNameLength-1)
+	}
+
+	b := dt.dataStore[dBase7LanguageDriverNameIndex : dBase7LanguageDriverNameIndex+dBase7LanguageDriverNameLength]
+	clearBytes(b)
+	copy(b, name)
+	return nil
+}
+
+// assignAutoIncrementValues sets each dBase 7 autoincrement field of the record to the next value in sequence, as
+// kept in the field's descriptor in the header, which is advanced.
+func (dt *DbfTable) assignAutoIncrementValues(record []byte) {
+	if dt.format != DBase7 {
+		return
+	}
+
+	layout := dt.format.fieldDescriptorLayout()
+	for i := range dt.fields {
+		if dt.fields[i].fieldType != AutoIncrement {
+			continue
+		}
+
+		descriptor := dt.dataStore[dt.format.headerPrefixLength()+i*layout.descriptorLength:]
+		nextValue := descriptor[dBase7AutoIncrementNextValueIndex : dBase7AutoIncrementNextValueIndex+4]
+		value := binary.LittleEndian.Uint32(nextValue)
+
+		offset := dt.fieldOffset(i)
+		dt.encodeInt32(record[offset:offset+4], int32(value))
+
+		binary.LittleEndian.PutUint32(nextValue, value+1)
+		copy(dt.fields[i].fieldStore[dBase7AutoIncrementNextValueIndex:], nextValue)
+	}
+}
+
+// signature returns the file signature (the first byte of the header) for the format, with or without a memo file.
+func (f Format) signature(hasMemo bool) byte {
+	switch {
+	case f == DBaseIV && hasMemo:
+		return 0x8B
+	case f == FoxPro && hasMemo:
+		return 0xF5
+	case f == VisualFoxPro:
+		return 0x30 // the presence of a memo file is flagged in the table flags instead
+	case f == DBase7 && hasMemo:
+		return 0x8C
+	case f == DBase7:
+		return 0x04
+	case hasMemo:
+		return 0x83
+	default:
+		return 0x03
+	}
+}
+
+// formatFromSignature derives the format of a table from its file signature.
+func formatFromSignature(signature byte) Format {
+	switch signature {
+	case 0x8B, 0x7B, 0xCB:
+		return DBaseIV
+	case 0xF5, 0xFB:
+		return FoxPro
+	case 0x30, 0x31, 0x32:
+		return VisualFoxPro
+	case 0x04, 0x8C:
+		return DBase7
+	default:
+		return DBaseIII
+	}
+}
+
+// fieldDescriptorLayout describes where the parts of a field descriptor are stored, which varies with the format.
+type fieldDescriptorLayout struct {
+	descriptorLength int
+	nameLength       int // including the end-of-field-name marker
+	typeIndex        int
+	lengthIndex      int
+	decimalIndex     int
+}
+
+var (
+	standardFieldDescriptorLayout = fieldDescriptorLayout{
+		descriptorLength: fieldDescriptorLength,
+		nameLength:       fieldNameByteLength,
+		typeIndex:        11,
+		lengthIndex:      16,
+		decimalIndex:     17,
+	}
+
+	dBase7FieldDescriptorLayout = fieldDescriptorLayout{
+		descriptorLength: dBase7FieldDescriptorLength,
+		nameLength:       dBase7FieldNameByteLength,
+		typeIndex:        32,
+		lengthIndex:      33,
+		decimalIndex:     34,
+	}
+)
+
+// fieldDescriptorLayout returns the layout of the field descriptors for the format.
+func (f Format) fieldDescriptorLayout() fieldDescriptorLayout {
+	if f == DBase7 {
+		return dBase7FieldDescriptorLayout
+	}
+	return standardFieldDescriptorLayout
+}
+
+// maxUsableNameByteLength returns the maximum length of a field name in bytes, which leaves room for the
+// end-of-field-name marker.
+func (l fieldDescriptorLayout) maxUsableNameByteLength() int {
+	return l.nameLength - 1
+}
+
+// headerPrefixLength returns the number of bytes in the header that precede the field descriptors.
+func (f Format) headerPrefixLength() int {
+	if f == DBase7 {
+		return dBase7HeaderPrefixLength
+	}
+	return 32
+}
+
+// usesFpt returns true if the format keeps memo values in a FoxPro .FPT file.
+func (f Format) usesFpt() bool {
+	return f == FoxPro || f == VisualFoxPro
+}
+
+// memoBlockPointerLength returns the length of the memo block pointers stored in memo fields.
+func (f Format) memoBlockPointerLength() byte {
+	if f == VisualFoxPro {
+		return binaryMemoBlockPointerLength
+	}
+	return memoBlockPointerLength
+}
+
+// fieldLength returns the length of a field of the given type in the format, which is fixed for types other than
+// Character, Numeric and Float, whatever the length given.
+func (f Format) fieldLength(fieldType DbaseDataType, length byte) byte {
+	switch {
+	case fieldType.usesMemo():
+		return f.memoBlockPointerLength()
+	case fieldType.fixedFieldLength() != notApplicable:
+		return fieldType.fixedFieldLength()
+	}
+	return length
+}
+
+// backlinkLength returns the number of bytes reserved after the field terminator in the header.
+func (f Format) backlinkLength() int {
+	if f == VisualFoxPro {
+		return vfpBacklinkLength
+	}
+	return 0
+}
+
+// memoFileExtension returns the file name extension of the memo file used by the format, without the leading dot.
+func (f Format) memoFileExtension() string {
+	if f.usesFpt() {
+		return "fpt"
+	}
+	return "dbt"
+}
+
+// newMemoFile creates an empty memo file of the kind used by the format.
+func (f Format) newMemoFile() memoFile {
+	if f.usesFpt() {
+		return newFptMemo()
+	}
+	return newDbtMemo(f == DBaseIV || f == DBase7)
+}
+
+// loadMemoFile interprets raw memo file content of the kind used by the format.
+func (f Format) loadMemoFile(data []byte) (memoFile, error) {
+	if f.usesFpt() {
+		return loadFptMemo(data)
+	}
+	return loadDbtMemo(data, f == DBaseIV || f == DBase7)
+}
@@ -0,0 +1,196 @@
+package godbf
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createVisualFoxProTable(t *testing.T) *DbfTable {
+	table := New(nil, WithFormat(VisualFoxPro))
+	return requireTestTable(t, table, 0,
+		table.AddTextField("NAME", 10), table.AddIntegerField("COUNT"), table.AddMemoField("NOTES"))
+}
+
+func TestNew_VisualFoxPro_HeaderLayout(t *testing.T) {
+	table := createVisualFoxProTable(t)
+
+	require.EqualValues(t, table.dataStore[0], 0x30)
+	require.EqualValues(t, table.dataStore[tableFlagsIndex]&vfpTableHasMemoFlag, vfpTableHasMemoFlag)
+	require.EqualValues(t, table.numberOfBytesInHeader, 32+3*fieldDescriptorLength+1+vfpBacklinkLength)
+	require.EqualValues(t, table.dataStore[32+3*fieldDescriptorLength], fieldTerminatorMarker)
+	require.EqualValues(t, table.Fields()[2].Length(), binaryMemoBlockPointerLength)
+
+	// displacement of each field within the record
+	for i, expected := range []byte{1, 11, 15} {
+		require.Equal(t, table.Fields()[i].fieldStore[fieldDisplacementIndex], expected)
+	}
+}
+
+func TestNew_VisualFoxPro_SaveAndLoad(t *testing.T) {
+	table := createVisualFoxProTable(t)
+	require.Nil(t, table.SetBacklink("..\\data\\sales.dbc"))
+
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Nil(t, table.SetFieldValueByName(row, "NAME", "name"))
+	require.Nil(t, table.SetFieldValueByName(row, "COUNT", "42"))
+	require.Nil(t, table.SetFieldValueByName(row, "NOTES", "notes"))
+
+	tempFilename := filepath.Join("testdata", "tempVisualFoxProTable.dbf")
+	require.Nil(t, table.Save(tempFilename, os.ModePerm))
+	defer os.Remove(tempFilename)
+	defer os.Remove(filepath.Join("testdata", "tempVisualFoxProTable.fpt"))
+
+	tableUnderTest, err := NewFromFile(tempFilename, nil)
+	require.Nil(t, err)
+	require.Equal(t, tableUnderTest.format, VisualFoxPro)
+	require.Equal(t, tableUnderTest.FieldNames(), []string{"NAME", "COUNT", "NOTES"})
+	require.Equal(t, tableUnderTest.GetRowAsSlice(row), []string{"name", "42", "notes"})
+	require.Equal(t, tableUnderTest.Backlink(), "..\\data\\sales.dbc")
+}
+
+func TestDbfTable_SetBacklink_Errors(t *testing.T) {
+	require.NotNil(t, New(nil).SetBacklink("sales.dbc"))
+	require.NotNil(t, createVisualFoxProTable(t).SetBacklink(strings.Repeat("x", vfpBacklinkLength+1)))
+	require.Equal(t, New(nil).Backlink(), "")
+}
+
+func TestNewFromByteArray_VisualFoxPro_FieldFlags(t *testing.T) {
+	table := createVisualFoxProTable(t)
+	table.dataStore[32+fieldFlagsIndex] = nullableFieldFlag | binaryFieldFlag
+	table.dataStore[32+fieldDescriptorLength+fieldFlagsIndex] = systemFieldFlag | autoIncrementFieldFlag
+
+	tableUnderTest, err := NewFromByteArray(table.dataStore, nil)
+	require.Nil(t, err)
+
+	fields := tableUnderTest.Fields()
+	require.True(t, fields[0].IsNullable())
+	require.True(t, fields[0].IsBinary())
+	require.False(t, fields[0].IsSystem())
+	require.False(t, fields[0].IsAutoIncrement())
+
+	require.True(t, fields[1].IsSystem())
+	require.True(t, fields[1].IsAutoIncrement())
+	require.False(t, fields[1].IsNullable())
+	require.False(t, fields[1].IsBinary())
+
+	require.False(t, fields[2].IsSystem())
+}
+
+func TestNewFromByteArray_FieldTerminatorMissing_Errors(t *testing.T) {
+	table := New(nil)
+	require.Nil(t, table.AddTextField("NAME", 10))
+	table.dataStore[32+fieldDescriptorLength] = 'X'
+
+	_, err := NewFromByteArray(table.dataStore, nil)
+	require.NotNil(t, err)
+	t.Log(err)
+}
+
+func createDBase7Table(t *testing.T) *DbfTable {
+	table := New(nil, WithFormat(DBase7))
+	return requireTestTable(t, table, 0,
+		table.SetLanguageDriverName("DBWINUS0"),
+		table.AddAutoIncrementField("ID"),
+		table.AddTextField("CUSTOMER_LAST_NAME_FOR_INVOICES", 20),
+		table.AddIntegerField("QUANTITY"),
+		table.AddDouble7Field("PRICE"),
+		table.AddTimestampField("ORDERED_AT"),
+		table.AddMemoField("NOTES"))
+}
+
+func TestNew_DBase7_HeaderLayout(t *testing.T) {
+	table := createDBase7Table(t)
+
+	require.EqualValues(t, table.dataStore[0], 0x8C)
+	require.EqualValues(t, table.numberOfBytesInHeader, dBase7HeaderPrefixLength+6*dBase7FieldDescriptorLength+1)
+	require.Equal(t, table.LanguageDriverName(), "DBWINUS0")
+
+	nameField := table.dataStore[dBase7HeaderPrefixLength+dBase7FieldDescriptorLength:]
+	require.Equal(t, string(nameField[:31]), "CUSTOMER_LAST_NAME_FOR_INVOICES")
+	require.EqualValues(t, nameField[31], endOfFieldNameMarker)
+	require.EqualValues(t, nameField[32], Character)
+	require.EqualValues(t, nameField[33], 20)
+}
+
+func TestNew_DBase7_FieldNameTruncatedTo31Bytes(t *testing.T) {
+	table := New(nil, WithFormat(DBase7))
+	require.Nil(t, table.AddTextField(strings.Repeat("N", 40), 10))
+	require.Equal(t, table.FieldNames(), []string{strings.Repeat("N", 31)})
+}
+
+func TestNew_DBase7_SaveAndLoad(t *testing.T) {
+	table := createDBase7Table(t)
+
+	for i, price := range []string{"-1.25", "1e10"} {
+		row, err := table.AddNewRecord()
+		require.Nil(t, err)
+		require.Equal(t, row, i)
+		require.Nil(t, table.SetFieldValueByName(row, "CUSTOMER_LAST_NAME_FOR_INVOICES", "Smith"))
+		require.Nil(t, table.SetFieldValueByName(row, "QUANTITY", "-7"))
+		require.Nil(t, table.SetFieldValueByName(row, "PRICE", price))
+		require.Nil(t, table.SetFieldValueByName(row, "ORDERED_AT", "20240131235959"))
+		require.Nil(t, table.SetFieldValueByName(row, "NOTES", "deliver to the back door"))
+	}
+
+	tempFilename := filepath.Join("testdata", "tempDBase7Table.dbf")
+	require.Nil(t, table.Save(tempFilename, os.ModePerm))
+	defer os.Remove(tempFilename)
+	defer os.Remove(filepath.Join("testdata", "tempDBase7Table.dbt"))
+
+	tableUnderTest, err := NewFromFile(tempFilename, nil)
+	require.Nil(t, err)
+	require.Equal(t, tableUnderTest.Format(), DBase7)
+	require.Equal(t, tableUnderTest.LanguageDriverName(), "DBWINUS0")
+	require.Equal(t, tableUnderTest.FieldNames(),
+		[]string{"ID", "CUSTOMER_LAST_NAME_FOR_INVOICES", "QUANTITY", "PRICE", "ORDERED_AT", "NOTES"})
+	require.Equal(t, tableUnderTest.GetRowAsSlice(0),
+		[]string{"1", "Smith", "-7", "-1.25", "20240131235959", "deliver to the back door"})
+	require.Equal(t, tableUnderTest.GetRowAsSlice(1),
+		[]string{"2", "Smith", "-7", "10000000000", "20240131235959", "deliver to the back door"})
+
+	// Autoincrement values continue from where they were left off.
+	row, err := tableUnderTest.AddNewRecord()
+	require.Nil(t, err)
+	id, err := tableUnderTest.Int32FieldValueByName(row, "ID")
+	require.Nil(t, err)
+	require.EqualValues(t, id, 3)
+	require.Equal(t, tableUnderTest.FieldValue(row, 2), "")
+}
+
+func TestDBase7_BinaryValuesSortBytewise(t *testing.T) {
+	table := New(nil, WithFormat(DBase7))
+
+	previous := make([]byte, 8)
+	for i, f := range []float64{-100, -1.5, 0, 0.25, 3, 1e6} {
+		b := make([]byte, 8)
+		table.encodeFloat64(b, f)
+		require.Equal(t, table.decodeFloat64(b), f)
+		if i > 0 {
+			require.Equal(t, bytes.Compare(previous, b), -1)
+		}
+		previous = b
+	}
+
+	previous = make([]byte, 4)
+	for i, n := range []int32{-100, -1, 0, 1, 100} {
+		b := make([]byte, 4)
+		table.encodeInt32(b, n)
+		require.Equal(t, table.decodeInt32(b), n)
+		if i > 0 {
+			require.Equal(t, bytes.Compare(previous, b), -1)
+		}
+		previous = b
+	}
+}
+
+func TestDbfTable_SetLanguageDriverName_Errors(t *testing.T) {
+	require.NotNil(t, New(nil).SetLanguageDriverName("DBWINUS0"))
+	require.NotNil(t, New(nil, WithFormat(DBase7)).SetLanguageDriverName(strings.Repeat("x", 32)))
+	require.Equal(t, New(nil).LanguageDriverName(), "")
+}
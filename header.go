@@ -27,3 +27,42 @@ func (h *header) SetNumberOfBytesInHeaderFromBytes(s []byte) {
 func (h *header) SetLengthOfEachRecordFromBytes(s []byte) {
 	h.lengthOfEachRecord = uint16(s[0]) | (uint16(s[1]) << 8)
 }
+
+// Header byte offsets of the table flags, as per https://www.dbase.com/Knowledgebase/INT/db7_file_fmt.htm, Heading 1.1.
+const (
+	incompleteTransactionIndex = 14
+	encryptionFlagIndex        = 15
+	tableFlagsIndex            = 28
+	languageDriverIDIndex      = 29
+
+	versionMask             byte = 0x07
+	productionMDXFlag       byte = 0x01
+	incompleteTransactionOn byte = 0x01
+	encryptionOn            byte = 0x01
+)
+
+// Version returns the version number held in bits 0-2 of the file signature, such as 3 for dBase III PLUS tables.
+func (dt *DbfTable) Version() byte {
+	return dt.fileSignature & versionMask
+}
+
+// HasProductionMDX returns true if the header flags the table as having a production index (.MDX) file.
+// For Visual FoxPro tables, the same flag denotes a structural compound index (.CDX) file.
+func (dt *DbfTable) HasProductionMDX() bool {
+	return dt.dataStore[tableFlagsIndex]&productionMDXFlag != 0
+}
+
+// IncompleteTransaction returns true if the header flags a dBase IV transaction as begun, but not ended.
+func (dt *DbfTable) IncompleteTransaction() bool {
+	return dt.dataStore[incompleteTransactionIndex] == incompleteTransactionOn
+}
+
+// Encrypted returns true if the header flags the table as encrypted by dBase IV.
+func (dt *DbfTable) Encrypted() bool {
+	return dt.dataStore[encryptionFlagIndex] == encryptionOn
+}
+
+// LanguageDriverID returns the language driver (code page) identifier stored in the header.
+func (dt *DbfTable) LanguageDriverID() byte {
+	return dt.dataStore[languageDriverIDIndex]
+}
@@ -3,31 +3,77 @@ package godbf
 import (
 	"bytes"
 	"fmt"
+	"io"
 
 	"golang.org/x/text/encoding"
 )
 
 // NewFromByteArray creates a DbfTable, reading it from a raw byte array, expecting the supplied encoding.
+// An encoding created by Auto() has the encoding detected from the table's language driver ID instead.
 func NewFromByteArray(data []byte, enc encoding.Encoding) (table *DbfTable, err error) {
-	// may have 0x1A at the end
-	if data[len(data)-1] == endOfFileMarker {
-		data = data[:len(data)-1]
-	}
+	return NewFromByteArrayWithMemo(data, nil, enc)
+}
 
+// NewFromByteArrayWithMemo creates a DbfTable, reading it from a raw byte array, and the content of its memo file from
+// a second byte array, expecting the supplied encoding. A nil memoData is permitted for tables without a memo file.
+func NewFromByteArrayWithMemo(data []byte, memoData []byte, enc encoding.Encoding) (table *DbfTable, err error) {
 	table = new(DbfTable)
-	table.useEncoding(enc)
+	table.useEncoding(resolveEncoding(enc, data))
 	if err = unpackHeader(data, table); err != nil {
 		return
 	}
 
-	table.dataStore = data
 	expectedSize := int(table.numberOfBytesInHeader) + int(table.numberOfRecords)*int(table.lengthOfEachRecord)
+
+	// may have 0x1A at the end, which is remembered so that it is written back on saving
+	if len(data) == expectedSize+1 && data[len(data)-1] == endOfFileMarker {
+		data = data[:len(data)-1]
+		table.hasEndOfFileMarker = true
+	}
+
+	table.dataStore = data
 	actualSize := len(data)
 	if actualSize != expectedSize {
 		err = fmt.Errorf("encoded content is %d bytes, but header expected %d", actualSize, expectedSize)
 		return
 	}
 
+	if memoData != nil {
+		if table.memo, err = table.format.loadMemoFile(memoData); err != nil {
+			return
+		}
+	}
+
+	lockSchema(table)
+	return
+}
+
+// readHeader reads a complete dBase header from the reader supplied, leaving the reader positioned at the first record.
+// The DbfTable returned describes the table's schema, holding only the header bytes in its dataStore.
+func readHeader(r io.Reader, enc encoding.Encoding) (table *DbfTable, err error) {
+	s := make([]byte, 32)
+	if _, err = io.ReadFull(r, s); err != nil {
+		return
+	}
+
+	table = new(DbfTable)
+	table.SetNumberOfBytesInHeaderFromBytes(s[8:10])
+	if table.numberOfBytesInHeader < 32 {
+		err = fmt.Errorf("header claims %d bytes, but at least 32 are required", table.numberOfBytesInHeader)
+		return
+	}
+
+	s = append(s, make([]byte, int(table.numberOfBytesInHeader)-len(s))...)
+	if _, err = io.ReadFull(r, s[32:]); err != nil {
+		return
+	}
+
+	table.useEncoding(resolveEncoding(enc, s))
+	if err = unpackHeader(s, table); err != nil {
+		return
+	}
+
+	table.dataStore = s
 	lockSchema(table)
 	return
 }
@@ -38,6 +84,7 @@ func unpackHeader(s []byte, dt *DbfTable) error {
 	dt.SetNumberOfRecordsFromBytes(s[4:8])
 	dt.SetNumberOfBytesInHeaderFromBytes(s[8:10])
 	dt.SetLengthOfEachRecordFromBytes(s[10:12])
+	dt.format = formatFromSignature(dt.fileSignature)
 
 	return unpackFields(s, dt)
 }
@@ -46,49 +93,96 @@ func unpackFields(s []byte, dt *DbfTable) (err error) {
 	// create fieldMap to translate field name to index
 	dt.fieldMap = make(map[string]int)
 
-	// Number of fields in dbase table
-	dt.numberOfFields = int((dt.numberOfBytesInHeader - 1 - 32) / 32)
-	for i := 0; i < dt.numberOfFields; i++ {
-		if err = unpackField(s, dt, i); err != nil {
+	// Field descriptors follow the first 32 bytes (68 for dBase 7) of the header, up to the field terminator. Anything after the
+	// terminator (such as the Visual FoxPro backlink) is not counted, as it varies with the format.
+	headerLength := int(dt.numberOfBytesInHeader)
+	if headerLength > len(s) {
+		headerLength = len(s)
+	}
+
+	layout := dt.format.fieldDescriptorLayout()
+	offset := dt.format.headerPrefixLength()
+	for ; offset < headerLength && s[offset] != fieldTerminatorMarker; offset += layout.descriptorLength {
+		if offset+layout.descriptorLength > headerLength {
+			return fmt.Errorf("field descriptor at offset %d exceeds the header length of %d bytes",
+				offset, headerLength)
+		}
+		if err = unpackField(s, dt, dt.numberOfFields, offset); err != nil {
 			return
 		}
+		dt.numberOfFields++
+	}
+
+	if offset >= headerLength {
+		return fmt.Errorf("field terminator missing from header of %d bytes", headerLength)
 	}
 	return
 }
 
-func unpackField(s []byte, dt *DbfTable, fieldIndex int) (err error) {
-	offset := (fieldIndex * 32) + 32
-
+func unpackField(s []byte, dt *DbfTable, fieldIndex int, offset int) (err error) {
 	var fieldName string
 	if fieldName, err = deriveFieldName(s, dt, offset); err != nil {
 		return
 	}
 
 	dt.fieldMap[fieldName] = fieldIndex
-
-	switch s[offset+11] {
-	case 'C':
-		err = dt.AddTextField(fieldName, s[offset+16])
-	case 'N':
-		err = dt.AddNumberField(fieldName, s[offset+16], s[offset+17])
-	case 'F':
-		err = dt.AddFloatField(fieldName, s[offset+16], s[offset+17])
-	case 'L':
+	numberOfFields := len(dt.fields)
+
+	layout := dt.format.fieldDescriptorLayout()
+	fieldType := DbaseDataType(s[offset+layout.typeIndex])
+	length := s[offset+layout.lengthIndex]
+	decimalPlaces := s[offset+layout.decimalIndex]
+
+	switch fieldType {
+	case Character:
+		err = dt.AddTextField(fieldName, length)
+	case Numeric:
+		err = dt.AddNumberField(fieldName, length, decimalPlaces)
+	case Float:
+		err = dt.AddFloatField(fieldName, length, decimalPlaces)
+	case Logical:
 		err = dt.AddBooleanField(fieldName)
-	case 'D':
+	case Date:
 		err = dt.AddDateField(fieldName)
+	case Integer, Currency, DateTime, AutoIncrement, Timestamp, Double7:
+		err = dt.addField(fieldName, fieldType, length, notApplicable)
+	case Double:
+		// dBase uses 'B' for 10-byte binary memo block pointers, while Visual FoxPro uses it for 8-byte doubles
+		if length == Double.fixedFieldLength() {
+			err = dt.AddDoubleField(fieldName, decimalPlaces)
+		} else {
+			err = dt.addRawField(fieldName, fieldType, length, decimalPlaces)
+		}
+	case Memo, General, Picture:
+		// Visual FoxPro stores memo block pointers in 4 bytes rather than 10, so keep the declared length
+		err = dt.addField(fieldName, fieldType, length, notApplicable)
+	default:
+		// keep fields of unknown types, so that the fields after them are still found at the right offsets
+		err = dt.addRawField(fieldName, fieldType, length, decimalPlaces)
+	}
+
+	if err != nil {
+		return
+	}
+
+	// keep the original descriptor, so that it is carried through unchanged when the header is rebuilt
+	fd := &dt.fields[numberOfFields]
+	copy(fd.fieldStore, s[offset:offset+layout.descriptorLength])
+	if dt.format == VisualFoxPro {
+		fd.flags = s[offset+fieldFlagsIndex]
 	}
 	return
 }
 
 func deriveFieldName(s []byte, dt *DbfTable, offset int) (fieldName string, err error) {
-	nameBytes := s[offset : offset+fieldNameByteLength]
+	nameLength := dt.format.fieldDescriptorLayout().nameLength
+	nameBytes := s[offset : offset+nameLength]
 
-	// Max usable field length is 10 bytes, where the 11th should contain the end of field marker.
+	// Max usable field length is 10 bytes (31 for dBase 7), where the last should contain the end of field marker.
 	endOfFieldIndex := bytes.Index(nameBytes, []byte{endOfFieldNameMarker})
 	if endOfFieldIndex == -1 {
 		err = fmt.Errorf("end-of-field marker missing from field bytes, offset [%d,%d]",
-			offset, offset+fieldNameByteLength)
+			offset, offset+nameLength)
 		return
 	}
 
@@ -100,15 +194,20 @@ func lockSchema(dt *DbfTable) {
 	dt.schemaLocked = true // Schema changes no longer permitted
 }
 
-// New creates a new dbase table from scratch for the given character encoding
-func New(enc encoding.Encoding) (table *DbfTable) {
+// New creates a new dbase table from scratch for the given character encoding. Unless configured otherwise via the
+// options supplied, the table is created in the DBaseIII format.
+func New(enc encoding.Encoding, options ...TableOption) (table *DbfTable) {
+	enc = resolveEncoding(enc, nil)
 	dt := new(DbfTable)
+	for _, option := range options {
+		option(dt)
+	}
 
 	// read dbase table header information
-	dt.fileSignature = 0x03
+	dt.fileSignature = dt.format.signature(false)
 	dt.RefreshLastUpdated()
 	dt.numberOfRecords = 0
-	dt.numberOfBytesInHeader = 32
+	dt.numberOfBytesInHeader = uint16(dt.format.headerPrefixLength())
 	dt.lengthOfEachRecord = 0
 	dt.fieldTerminator = 0x0D
 
@@ -118,9 +217,6 @@ func New(enc encoding.Encoding) (table *DbfTable) {
 	dt.fieldMap = make(map[string]int)
 	dt.schemaLocked = false
 
-	// Number of fields in dbase table
-	dt.numberOfFields = int((dt.numberOfBytesInHeader - 1 - 32) / 32)
-
 	s := make([]byte, dt.numberOfBytesInHeader+1) // +1 is for footer
 
 	// set DbfTable dataStore slice that will store the complete file in memory
@@ -132,19 +228,18 @@ func New(enc encoding.Encoding) (table *DbfTable) {
 	dt.dataStore[3] = dt.updateDay
 
 	// no MDX file (index upon demand)
-	dt.dataStore[28] = 0x00
+	dt.dataStore[tableFlagsIndex] = 0x00
 
 	// set dbase language driver
-	// Huston we have problem!
-	// There is no easy way to deal with encoding issues. At least at the moment
-	// I will try to find archaic encoding code defined by dbase standard (if there is any)
-	// for given encoding. If none match I will go with default ANSI.
+	// The language driver ID is looked up among the code pages registered for the given encoding (see
+	// RegisterCodePage). Without an encoding, default ANSI is used. An encoding without a registered ID is marked
+	// as having no language driver, rather than one that would decode it wrongly.
 	//
 	// Despite this flag in set in dbase file, I will continue to use provide encoding for
 	// everything except this file encoding flag.
 	//
 	// Why? To make sure at least if you know the real encoding you can process text accordingly.
-	dt.dataStore[29] = codePageID(enc)
+	dt.dataStore[languageDriverIDIndex] = codePageID(enc)
 	dt.updateHeader()
 	return dt
 }
@@ -1,22 +1,78 @@
 package godbf
 
 import (
+	"errors"
+	"io/fs"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"golang.org/x/text/encoding"
 )
 
 // NewFromFile creates a DbfTable, reading it from a file with the given file name, expecting the supplied encoding.
+// If the table has memo fields, its memo file is read from alongside the table file when present.
+// An encoding created by Auto() has the encoding detected from the table's language driver ID, or failing that, from a
+// .cpg file alongside the table file.
 func NewFromFile(fileName string, enc encoding.Encoding) (table *DbfTable, err error) {
 	var data []byte
 	if data, err = ioutil.ReadFile(fileName); err != nil {
 		return
 	}
-	return NewFromByteArray(data, enc)
+
+	if _, ok := enc.(autoEncoding); ok {
+		var codePageFileContent []byte
+		if codePageFileContent, err = readCompanionFile(fileName, codePageFileExtension); err != nil {
+			return
+		}
+		enc = withCodePageFile(enc, codePageFileContent)
+	}
+
+	if table, err = NewFromByteArray(data, enc); err != nil || !table.hasMemoFields() {
+		return
+	}
+
+	var memoData []byte
+	if memoData, err = readCompanionFile(fileName, table.format.memoFileExtension()); err != nil {
+		return
+	}
+	if memoData != nil {
+		table.memo, err = table.format.loadMemoFile(memoData)
+	}
+	return
 }
 
-// Save saves the supplied DbfTable to a file of the specified filename
-func (dt *DbfTable) Save(filename string, fileMode os.FileMode) error {
-	return ioutil.WriteFile(filename, dt.dataStore, fileMode)
+// codePageFileExtension is the extension of the file naming the code page of a table, as written by GIS software.
+const codePageFileExtension = "cpg"
+
+// readCompanionFile reads the file with the given extension accompanying the table file of the given name, such as its
+// memo file, trying both lower and upper case extensions. If there is no such file, nil is returned.
+func readCompanionFile(fileName string, extension string) (data []byte, err error) {
+	base := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	for _, name := range []string{
+		memoFileName(fileName, extension),
+		base + "." + strings.ToLower(extension),
+		base + "." + strings.ToUpper(extension),
+	} {
+		if data, err = ioutil.ReadFile(name); err == nil || !errors.Is(err, fs.ErrNotExist) {
+			return
+		}
+	}
+	return nil, nil
+}
+
+// Save saves the supplied DbfTable to a file of the specified filename.
+// If the table has a memo file, it is saved alongside, with the extension matching the case of the filename's.
+// A table loaded with a trailing end-of-file marker is saved with one, so that an unmodified table is saved unchanged.
+func (dt *DbfTable) Save(filename string, fileMode os.FileMode) (err error) {
+	data := dt.dataStore
+	if dt.hasEndOfFileMarker {
+		data = append(data[:len(data):len(data)], endOfFileMarker)
+	}
+
+	if err = ioutil.WriteFile(filename, data, fileMode); err != nil || dt.memo == nil {
+		return
+	}
+	return ioutil.WriteFile(memoFileName(filename, dt.format.memoFileExtension()), dt.memo.bytes(), fileMode)
 }
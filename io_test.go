@@ -185,3 +185,53 @@ func TestSaveToFile_CreateErrors_Errors(t *testing.T) {
 	require.NotNil(t, err)
 	t.Log(err)
 }
+
+// TestSave_GoldenFiles_RoundTripIsLossless loads each table of the golden-file corpus, saves it unmodified, and
+// expects the table file and its memo file, if any, to be byte-identical to the originals.
+func TestSave_GoldenFiles_RoundTripIsLossless(t *testing.T) {
+	goldenFiles, err := filepath.Glob(filepath.Join("testdata", "golden", "*.dbf"))
+	require.Nil(t, err)
+	require.NotEmpty(t, goldenFiles)
+	goldenFiles = append(goldenFiles, validTestFile)
+
+	for _, goldenFile := range goldenFiles {
+		t.Run(filepath.Base(goldenFile), func(t *testing.T) {
+			table, err := NewFromFile(goldenFile, nil)
+			require.Nil(t, err)
+
+			savedFile := filepath.Join(t.TempDir(), filepath.Base(goldenFile))
+			require.Nil(t, table.Save(savedFile, 0644))
+			requireSameFileContent(t, goldenFile, savedFile)
+
+			if table.hasMemoFields() {
+				extension := table.Format().memoFileExtension()
+				requireSameFileContent(t, memoFileName(goldenFile, extension), memoFileName(savedFile, extension))
+			}
+		})
+	}
+}
+
+func requireSameFileContent(t *testing.T, expectedFile string, actualFile string) {
+	expected, err := ioutil.ReadFile(expectedFile)
+	require.Nil(t, err)
+	actual, err := ioutil.ReadFile(actualFile)
+	require.Nil(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func TestNewFromFile_HeaderFlags(t *testing.T) {
+	table, err := NewFromFile(filepath.Join("testdata", "golden", "flags.dbf"), nil)
+	require.Nil(t, err)
+	require.EqualValues(t, 3, table.Version())
+	require.True(t, table.HasProductionMDX())
+	require.True(t, table.IncompleteTransaction())
+	require.True(t, table.Encrypted())
+	require.EqualValues(t, 0x57, table.LanguageDriverID())
+
+	table, err = NewFromFile(filepath.Join("testdata", "golden", "dbase7.dbf"), nil)
+	require.Nil(t, err)
+	require.EqualValues(t, 4, table.Version())
+	require.False(t, table.HasProductionMDX())
+	require.False(t, table.IncompleteTransaction())
+	require.False(t, table.Encrypted())
+}
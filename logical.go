@@ -0,0 +1,76 @@
+package godbf
+
+import "fmt"
+
+// Logical field values, as written by SetBoolFieldValue().
+const (
+	logicalTrue  = 'T'
+	logicalFalse = 'F'
+)
+
+// BoolFieldValue returns the value of a Logical field given row number and field index provided. T, t, Y and y read
+// as true, while F, f, N and n read as false. A field that has not been initialised, holding '?' or a blank, is
+// reported as not valid. An error is returned if the field is not a Logical field, or if it holds any other value.
+func (dt *DbfTable) BoolFieldValue(row int, fieldIndex int) (value bool, valid bool, err error) {
+	var field []byte
+	if field, err = dt.logicalField(row, fieldIndex); err != nil {
+		return
+	}
+
+	switch field[0] {
+	case 'T', 't', 'Y', 'y':
+		return true, true, nil
+	case 'F', 'f', 'N', 'n':
+		return false, true, nil
+	case '?', ' ', null:
+		return false, false, nil
+	}
+	err = fmt.Errorf("invalid logical value '%c' in field \"%s\"", field[0], dt.fields[fieldIndex].name)
+	return
+}
+
+// BoolFieldValueByName returns the value of a Logical field given row number and name provided, as per
+// BoolFieldValue().
+func (dt *DbfTable) BoolFieldValueByName(row int, fieldName string) (value bool, valid bool, err error) {
+	if fieldIndex, entryFound := dt.fieldMap[fieldName]; entryFound {
+		return dt.BoolFieldValue(row, fieldIndex)
+	}
+	err = fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+	return
+}
+
+// SetBoolFieldValue sets the value of a Logical field given row number and field index provided, writing T for true
+// and F for false. An error is returned if the field is not a Logical field.
+func (dt *DbfTable) SetBoolFieldValue(row int, fieldIndex int, value bool) (err error) {
+	var field []byte
+	if field, err = dt.logicalField(row, fieldIndex); err != nil {
+		return
+	}
+
+	field[0] = logicalFalse
+	if value {
+		field[0] = logicalTrue
+	}
+	dt.clearNullFlag(dt.record(row), fieldIndex)
+	return
+}
+
+// SetBoolFieldValueByName sets the value of a Logical field given row number and name provided, as per
+// SetBoolFieldValue().
+func (dt *DbfTable) SetBoolFieldValueByName(row int, fieldName string, value bool) (err error) {
+	if fieldIndex, entryFound := dt.fieldMap[fieldName]; entryFound {
+		return dt.SetBoolFieldValue(row, fieldIndex, value)
+	}
+	return fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+}
+
+// logicalField returns the byte of the Logical field with the given index, for the record at the given row.
+func (dt *DbfTable) logicalField(row int, fieldIndex int) (field []byte, err error) {
+	if dt.fields[fieldIndex].fieldType != Logical {
+		err = fmt.Errorf("type of field \"%s\" is not Logical", dt.fields[fieldIndex].name)
+		return
+	}
+
+	offset := dt.fieldOffset(fieldIndex)
+	return dt.record(row)[offset : offset+int(dt.fields[fieldIndex].length)], nil
+}
@@ -0,0 +1,57 @@
+package godbf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newLogicalTestTable(t *testing.T) *DbfTable {
+	table := New(nil)
+	return requireTestTable(t, table, 1, table.AddTextField("NAME", 8), table.AddBooleanField("OK"))
+}
+
+func TestDbfTable_BoolFieldValue_TruthValues(t *testing.T) {
+	table := newLogicalTestTable(t)
+
+	for stored, expected := range map[string]struct{ value, valid bool }{
+		"T": {true, true}, "t": {true, true}, "Y": {true, true}, "y": {true, true},
+		"F": {false, true}, "f": {false, true}, "N": {false, true}, "n": {false, true},
+		"?": {false, false}, "": {false, false},
+	} {
+		require.Nil(t, table.SetFieldValue(0, 1, stored))
+		value, valid, err := table.BoolFieldValueByName(0, "OK")
+		require.Nil(t, err, stored)
+		require.Equal(t, expected.value, value, stored)
+		require.Equal(t, expected.valid, valid, stored)
+	}
+
+	table.record(0)[table.fieldOffset(1)] = null
+	_, valid, err := table.BoolFieldValue(0, 1)
+	require.Nil(t, err)
+	require.False(t, valid)
+}
+
+func TestDbfTable_SetBoolFieldValue(t *testing.T) {
+	table := newLogicalTestTable(t)
+
+	require.Nil(t, table.SetBoolFieldValueByName(0, "OK", true))
+	require.Equal(t, "T", table.FieldValue(0, 1))
+	require.Nil(t, table.SetBoolFieldValue(0, 1, false))
+	require.Equal(t, "F", table.FieldValue(0, 1))
+}
+
+func TestDbfTable_BoolFieldValue_Errors(t *testing.T) {
+	table := newLogicalTestTable(t)
+
+	require.Nil(t, table.SetFieldValue(0, 1, "X"))
+	_, _, err := table.BoolFieldValue(0, 1)
+	require.EqualError(t, err, "invalid logical value 'X' in field \"OK\"")
+
+	_, _, err = table.BoolFieldValueByName(0, "NAME")
+	require.EqualError(t, err, "type of field \"NAME\" is not Logical")
+	_, _, err = table.BoolFieldValueByName(0, "MISSING")
+	require.NotNil(t, err)
+	require.EqualError(t, table.SetBoolFieldValueByName(0, "NAME", true), "type of field \"NAME\" is not Logical")
+	require.NotNil(t, table.SetBoolFieldValueByName(0, "MISSING", true))
+}
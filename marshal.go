@@ -0,0 +1,455 @@
+package godbf
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// structTagName is the key of the struct tags mapping struct fields onto the fields of a table, such as
+// `dbf:"SOME_COLUMN_ID"`. A tag of "-" has the struct field ignored.
+const structTagName = "dbf"
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	ratType  = reflect.TypeOf((*big.Rat)(nil))
+)
+
+// ConversionErrors lists the values that could not be converted between the fields of a table and struct fields, by
+// Unmarshal(), UnmarshalAll(), Marshal() or AppendStruct().
+type ConversionErrors []ConversionFailure
+
+func (e ConversionErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%d values cannot be converted, such as at %v", len(e), e[0])
+}
+
+// mappedField is a struct field mapped onto a field of a table via its struct tag.
+type mappedField struct {
+	index      []int // as per reflect.Value.FieldByIndex()
+	fieldIndex int   // index of the field of the table
+}
+
+// Unmarshal sets the exported fields of the struct that v points to from the values of the record at the given row,
+// as mapped onto the table's fields by `dbf:"NAME"` struct tags. A tag without a name, such as `dbf:""`, maps onto a
+// field named after the struct field in upper case. Untagged struct fields are ignored, except for embedded structs,
+// whose fields are mapped in turn.
+//
+// Struct fields can be of string, []byte, bool, integer, floating point, time.Time and *big.Rat types, or pointers to
+// any of these, which are set to nil for NULL values, as per FieldIsNull(). Types implementing sql.Scanner, such as
+// sql.NullString, are passed the value returned by TypedFieldValue(). Integer and floating point struct fields can be
+// set from Character fields holding numbers. Values that cannot be converted are returned as ConversionErrors, once
+// all others have been set.
+func Unmarshal(table *DbfTable, row int, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Unmarshal requires a non-nil pointer to a struct, not %T", v)
+	}
+	if row < 0 || !table.HasRecord(row) {
+		return fmt.Errorf("row %d does not exist", row)
+	}
+
+	fields, err := table.mapStructFields(rv.Elem().Type())
+	if err != nil {
+		return err
+	}
+	if failures := table.unmarshalRecord(row, rv.Elem(), fields); len(failures) > 0 {
+		return failures
+	}
+	return nil
+}
+
+// UnmarshalAll sets the slice that v points to, of structs or of pointers to structs, from the records of the table
+// that are not marked as deleted, as per Unmarshal(). Values that cannot be converted are returned as
+// ConversionErrors, once all others have been set.
+func UnmarshalAll(table *DbfTable, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("UnmarshalAll requires a non-nil pointer to a slice of structs, not %T", v)
+	}
+
+	sliceType := rv.Elem().Type()
+	structType := sliceType.Elem()
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("UnmarshalAll requires a non-nil pointer to a slice of structs, not %T", v)
+	}
+
+	fields, err := table.mapStructFields(structType)
+	if err != nil {
+		return err
+	}
+
+	var failures ConversionErrors
+	slice := reflect.MakeSlice(sliceType, 0, table.NumberOfRecords())
+	for row := 0; row < table.NumberOfRecords(); row++ {
+		if table.RowIsDeleted(row) {
+			continue
+		}
+
+		elem := reflect.New(structType)
+		failures = append(failures, table.unmarshalRecord(row, elem.Elem(), fields)...)
+		if sliceType.Elem().Kind() == reflect.Ptr {
+			slice = reflect.Append(slice, elem)
+		} else {
+			slice = reflect.Append(slice, elem.Elem())
+		}
+	}
+	rv.Elem().Set(slice)
+
+	if len(failures) > 0 {
+		return failures
+	}
+	return nil
+}
+
+// Marshal sets the values of the record at the given row from the exported fields of the struct v, or that v points
+// to, as mapped onto the table's fields by `dbf:"NAME"` struct tags, as per Unmarshal(). Values are converted as per
+// SetTypedFieldValue(), with nil pointers setting NULL values. Types implementing driver.Valuer, such as
+// sql.NullString, are converted via the value they return. Values that cannot be converted are returned as
+// ConversionErrors, once all others have been set.
+func Marshal(table *DbfTable, row int, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("Marshal requires a struct or a non-nil pointer to a struct, not %T", v)
+	}
+	if row < 0 || !table.HasRecord(row) {
+		return fmt.Errorf("row %d does not exist", row)
+	}
+
+	fields, err := table.mapStructFields(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	var failures ConversionErrors
+	for _, field := range fields {
+		fv := rv.FieldByIndex(field.index)
+		if err := table.marshalField(row, field.fieldIndex, fv); err != nil {
+			failures = append(failures, ConversionFailure{
+				Row:   row,
+				Field: table.fields[field.fieldIndex].name,
+				Value: fmt.Sprint(fv.Interface()),
+				Err:   err,
+			})
+		}
+	}
+
+	if len(failures) > 0 {
+		return failures
+	}
+	return nil
+}
+
+// AppendStruct adds a new record to the table, setting its values from the struct v, or that v points to, as per
+// Marshal(). The row of the new record is returned. Should any values not be converted, the record is kept with those
+// values left blank.
+func AppendStruct(table *DbfTable, v interface{}) (row int, err error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return -1, fmt.Errorf("AppendStruct requires a struct or a non-nil pointer to a struct, not %T", v)
+	}
+
+	if row, err = table.AddNewRecord(); err != nil {
+		return
+	}
+	err = Marshal(table, row, v)
+	return
+}
+
+// taggedField is an exported struct field mapped onto a field of a table by its struct tag.
+type taggedField struct {
+	reflect.StructField
+	index   []int    // as per reflect.Value.FieldByIndex()
+	name    string   // name of the field of the table
+	options []string // options of the tag following the name, such as "len=12"
+}
+
+// taggedStructFields returns the exported fields of the struct type given that are tagged with a field name, in
+// order, including those of embedded structs that are not tagged themselves. A tag without a name maps the struct
+// field onto a field named after it in upper case.
+func taggedStructFields(structType reflect.Type) (fields []taggedField) {
+	for i := 0; i < structType.NumField(); i++ {
+		sf := structType.Field(i)
+		tag, tagged := sf.Tag.Lookup(structTagName)
+
+		if !tagged && sf.Anonymous && sf.Type.Kind() == reflect.Struct {
+			for _, field := range taggedStructFields(sf.Type) {
+				field.index = append([]int{i}, field.index...)
+				fields = append(fields, field)
+			}
+			continue
+		}
+
+		name, options := parseStructTag(tag)
+		if !tagged || name == "-" || sf.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToUpper(sf.Name)
+		}
+		fields = append(fields, taggedField{StructField: sf, index: []int{i}, name: name, options: options})
+	}
+	return
+}
+
+// mapStructFields maps the exported, tagged fields of the struct type given onto the fields of the table. An error is
+// returned if a tag names a field that does not exist.
+func (dt *DbfTable) mapStructFields(structType reflect.Type) (fields []mappedField, err error) {
+	for _, field := range taggedStructFields(structType) {
+		fieldIndex, found := dt.fieldMap[field.name]
+		if !found {
+			return nil, fmt.Errorf("Field name \"%s\" does not exist", field.name)
+		}
+		fields = append(fields, mappedField{index: field.index, fieldIndex: fieldIndex})
+	}
+	return
+}
+
+// parseStructTag splits a struct tag into the name of the field it maps onto, and any options following it.
+func parseStructTag(tag string) (name string, options []string) {
+	parts := strings.Split(tag, ",")
+	return strings.TrimSpace(parts[0]), parts[1:]
+}
+
+// unmarshalRecord sets the mapped fields of the struct from the record at the given row, returning the values that
+// could not be converted.
+func (dt *DbfTable) unmarshalRecord(row int, rv reflect.Value, fields []mappedField) (failures ConversionErrors) {
+	for _, field := range fields {
+		if err := dt.unmarshalField(row, field.fieldIndex, rv.FieldByIndex(field.index)); err != nil {
+			failures = append(failures, ConversionFailure{
+				Row:   row,
+				Field: dt.fields[field.fieldIndex].name,
+				Value: dt.FieldValue(row, field.fieldIndex),
+				Err:   err,
+			})
+		}
+	}
+	return
+}
+
+// unmarshalField sets the struct field from the value of the field with the given index, for the record at the
+// given row.
+func (dt *DbfTable) unmarshalField(row int, fieldIndex int, fv reflect.Value) error {
+	if scanner, ok := fv.Addr().Interface().(sql.Scanner); ok {
+		value, err := dt.TypedFieldValue(row, fieldIndex)
+		if err != nil {
+			return err
+		}
+		return scanner.Scan(value)
+	}
+
+	if fv.Type() == ratType {
+		if dt.FieldIsNull(row, fieldIndex) {
+			fv.Set(reflect.Zero(ratType))
+			return nil
+		}
+		r, err := dt.RatFieldValueByName(row, dt.fields[fieldIndex].name)
+		if err == nil {
+			fv.Set(reflect.ValueOf(r))
+		}
+		return err
+	}
+
+	if fv.Kind() == reflect.Ptr {
+		if dt.FieldIsNull(row, fieldIndex) {
+			fv.Set(reflect.Zero(fv.Type()))
+			return nil
+		}
+		elem := reflect.New(fv.Type().Elem())
+		if err := dt.unmarshalField(row, fieldIndex, elem.Elem()); err != nil {
+			return err
+		}
+		fv.Set(elem)
+		return nil
+	}
+
+	if fv.Kind() == reflect.String {
+		value, err := dt.sourceValue(dt.record(row), fieldIndex)
+		if err == nil {
+			fv.SetString(value)
+		}
+		return err
+	}
+
+	value, err := dt.TypedFieldValue(row, fieldIndex)
+	if err != nil {
+		return err
+	}
+	return assignValue(fv, value)
+}
+
+// assignValue sets the struct field from a value returned by TypedFieldValue(), converting it to the struct field's
+// type where this can be done without loss. Strings, such as numbers held by Character fields, are parsed for
+// numeric struct fields. Nil and blank strings set the zero value.
+func assignValue(fv reflect.Value, value interface{}) error {
+	if s, ok := value.(string); ok && isNumericKind(fv.Kind()) {
+		if s = strings.TrimSpace(s); s == "" {
+			value = nil
+		} else {
+			value = s
+		}
+	}
+	if value == nil {
+		fv.Set(reflect.Zero(fv.Type()))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := integerValue(value)
+		if err != nil {
+			return err
+		}
+		if fv.OverflowInt(i) {
+			return fmt.Errorf("value %d overflows %s", i, fv.Type())
+		}
+		fv.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := integerValue(value)
+		if err != nil {
+			return err
+		}
+		if i < 0 || fv.OverflowUint(uint64(i)) {
+			return fmt.Errorf("value %d overflows %s", i, fv.Type())
+		}
+		fv.SetUint(uint64(i))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch v := value.(type) {
+		case int64:
+			f = float64(v)
+		case float64:
+			f = v
+		case string:
+			var err error
+			if f, err = strconv.ParseFloat(v, 64); err != nil {
+				return fmt.Errorf("invalid number \"%s\"", v)
+			}
+		default:
+			return fmt.Errorf("cannot convert %T to %s", value, fv.Type())
+		}
+		if fv.OverflowFloat(f) {
+			return fmt.Errorf("value %v overflows %s", f, fv.Type())
+		}
+		fv.SetFloat(f)
+		return nil
+	case reflect.Bool:
+		if b, ok := value.(bool); ok {
+			fv.SetBool(b)
+			return nil
+		}
+	case reflect.Slice:
+		if b, ok := value.([]byte); ok && fv.Type().Elem().Kind() == reflect.Uint8 {
+			fv.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+	case reflect.Struct:
+		if t, ok := value.(time.Time); ok && fv.Type() == timeType {
+			fv.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot convert %T to %s", value, fv.Type())
+}
+
+// isNumericKind reports whether struct fields of the kind hold integers or floating point numbers.
+func isNumericKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Uint64 || kind == reflect.Float32 || kind == reflect.Float64
+}
+
+// integerValue converts a value returned by TypedFieldValue(), or a string holding a number, to an integer, provided
+// it is a whole number.
+func integerValue(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case string:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number \"%s\"", v)
+		}
+		return integerValue(f)
+	case float64:
+		if v != math.Trunc(v) || v < math.MinInt64 || v >= math.MaxInt64 {
+			return 0, fmt.Errorf("value %v is not a whole number", v)
+		}
+		return int64(v), nil
+	}
+	return 0, fmt.Errorf("cannot convert %T to an integer", value)
+}
+
+// marshalField sets the value of the field with the given index, for the record at the given row, from the struct
+// field.
+func (dt *DbfTable) marshalField(row int, fieldIndex int, fv reflect.Value) error {
+	if fv.Kind() == reflect.Ptr && fv.IsNil() {
+		return dt.SetTypedFieldValue(row, fieldIndex, nil)
+	}
+
+	if valuer, ok := fv.Interface().(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return err
+		}
+		return dt.SetTypedFieldValue(row, fieldIndex, value)
+	}
+
+	if fv.Type() == ratType {
+		return dt.SetTypedFieldValue(row, fieldIndex, fv.Interface())
+	}
+	if fv.Kind() == reflect.Ptr {
+		fv = fv.Elem()
+	}
+
+	var value interface{}
+	switch fv.Kind() {
+	case reflect.String:
+		value = fv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = fv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value = fv.Uint()
+	case reflect.Float32:
+		// formatted as float32, so that 0.1 is stored as such rather than as 0.10000000149011612
+		f, _ := strconv.ParseFloat(strconv.FormatFloat(fv.Float(), 'g', -1, 32), 64)
+		value = f
+	case reflect.Float64:
+		value = fv.Float()
+	case reflect.Bool:
+		value = fv.Bool()
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("cannot convert %s", fv.Type())
+		}
+		value = fv.Bytes()
+	case reflect.Struct:
+		if fv.Type() != timeType {
+			return fmt.Errorf("cannot convert %s", fv.Type())
+		}
+		value = fv.Interface()
+	default:
+		return fmt.Errorf("cannot convert %s", fv.Type())
+	}
+	return dt.SetTypedFieldValue(row, fieldIndex, value)
+}
@@ -0,0 +1,200 @@
+package godbf
+
+import (
+	"database/sql"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+type marshalAudit struct {
+	Checked bool `dbf:"CHECKED"`
+}
+
+type marshalCustomer struct {
+	marshalAudit
+	Name     string         `dbf:"NAME"`
+	Age      int            `dbf:"AGE"`
+	Balance  float64        `dbf:"BALANCE"`
+	Born     time.Time      `dbf:"BORN"`
+	Visits   *int           `dbf:"VISITS"`
+	Notes    sql.NullString `dbf:"NOTES"`
+	Ignored  string         `dbf:"-"`
+	Untagged string
+}
+
+func createMarshalTable(t *testing.T) *DbfTable {
+	table := New(charmap.CodePage866, WithFormat(DBaseIV))
+	return requireTestTable(t, table, 0,
+		table.AddTextField("NAME", 10),
+		table.AddNumberField("AGE", 3, 0),
+		table.AddNumberField("BALANCE", 10, 2),
+		table.AddDateField("BORN"),
+		table.AddNumberField("VISITS", 4, 0),
+		table.AddMemoField("NOTES"),
+		table.AddBooleanField("CHECKED"))
+}
+
+func TestMarshal_AppendStructAndUnmarshalAll(t *testing.T) {
+	table := createMarshalTable(t)
+	visits := 12
+	born := time.Date(1980, time.May, 17, 0, 0, 0, 0, time.UTC)
+	customers := []marshalCustomer{
+		{
+			marshalAudit: marshalAudit{Checked: true},
+			Name:         "Иван",
+			Age:          42,
+			Balance:      1234.565,
+			Born:         born,
+			Visits:       &visits,
+			Notes:        sql.NullString{String: "постоянный клиент", Valid: true},
+			Ignored:      "ignored",
+		},
+		{Name: "Мария"},
+	}
+
+	for _, customer := range customers {
+		_, err := AppendStruct(table, &customer)
+		require.Nil(t, err)
+	}
+	require.Equal(t, table.GetRowAsSlice(0),
+		[]string{"Иван", "42", "1234.57", "19800517", "12", "постоянный клиент", "T"})
+	require.Equal(t, table.GetRowAsSlice(1), []string{"Мария", "0", "0.00", "", "", "", "F"})
+
+	var loaded []*marshalCustomer
+	require.Nil(t, UnmarshalAll(table, &loaded))
+	require.Len(t, loaded, 2)
+
+	first := loaded[0]
+	require.True(t, first.Checked)
+	require.Equal(t, first.Name, "Иван")
+	require.Equal(t, first.Age, 42)
+	require.Equal(t, first.Balance, 1234.57)
+	require.True(t, first.Born.Equal(time.Date(1980, time.May, 17, 0, 0, 0, 0, time.Local)))
+	require.Equal(t, *first.Visits, 12)
+	require.Equal(t, first.Notes, sql.NullString{String: "постоянный клиент", Valid: true})
+	require.Equal(t, first.Ignored, "")
+
+	second := loaded[1]
+	require.Nil(t, second.Visits)
+	require.True(t, second.Born.IsZero())
+	require.Equal(t, second.Notes, sql.NullString{String: "", Valid: true})
+
+	var exact struct {
+		Balance *big.Rat `dbf:"BALANCE"`
+		Visits  *big.Rat `dbf:"VISITS"`
+	}
+	require.Nil(t, Unmarshal(table, 0, &exact))
+	require.Equal(t, exact.Balance, big.NewRat(123457, 100))
+	require.Nil(t, Unmarshal(table, 1, &exact))
+	require.Zero(t, exact.Balance.Sign())
+	require.Nil(t, exact.Visits)
+
+	// records marked as deleted are skipped
+	require.Nil(t, table.DeleteRecord(0))
+	var remaining []marshalCustomer
+	require.Nil(t, UnmarshalAll(table, &remaining))
+	require.Len(t, remaining, 1)
+	require.Equal(t, remaining[0].Name, "Мария")
+}
+
+func TestMarshal_ConversionErrors(t *testing.T) {
+	table := createMarshalTable(t)
+	row, err := AppendStruct(table, marshalCustomer{Name: "Константин", Age: 1000, Balance: 1})
+	var failures ConversionErrors
+	require.True(t, errors.As(err, &failures))
+	require.Len(t, failures, 1)
+	require.Equal(t, failures[0].Field, "AGE")
+	require.Equal(t, failures[0].Value, "1000")
+	require.EqualError(t, err, "row 0, field \"AGE\": value 1000 does not fit field \"AGE\" of length 3")
+	require.Equal(t, table.FieldValue(row, 2), "1.00")
+
+	var tooLong struct {
+		Name string `dbf:"NAME"`
+		Age  string `dbf:"AGE"`
+	}
+	tooLong.Name = "Константин Константинович"
+	tooLong.Age = "forty"
+	err = Marshal(table, row, tooLong)
+	require.True(t, errors.As(err, &failures))
+	require.Len(t, failures, 2)
+	require.EqualError(t, failures[0].Err, "value does not fit field of length 10")
+	require.EqualError(t, failures[1].Err, "invalid decimal value \"forty\"")
+
+	require.Nil(t, table.SetFieldValueByName(row, "AGE", "-5"))
+	var wrongTypes struct {
+		Age     uint8 `dbf:"AGE"`
+		Balance int   `dbf:"BALANCE"`
+		Name    bool  `dbf:"NAME"`
+	}
+	require.Nil(t, table.SetFieldValueByName(row, "BALANCE", "1.5"))
+	err = Unmarshal(table, row, &wrongTypes)
+	require.True(t, errors.As(err, &failures))
+	require.Len(t, failures, 3)
+	require.EqualError(t, failures[0].Err, "value -5 overflows uint8")
+	require.EqualError(t, failures[1].Err, "value 1.5 is not a whole number")
+	require.EqualError(t, failures[2].Err, "cannot convert string to bool")
+}
+
+func TestMarshal_NumbersInCharacterFields(t *testing.T) {
+	table := New(nil)
+	require.Nil(t, table.AddTextField("CODE", 6))
+	require.Nil(t, table.AddTextField("PRICE", 8))
+	require.Nil(t, table.AddTextField("COUNT", 6))
+	require.Nil(t, table.AddTextField("NOTE", 6))
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	type item struct {
+		Code  int     `dbf:"CODE"`
+		Price float64 `dbf:"PRICE"`
+		Count uint16  `dbf:"COUNT"`
+		Note  *int    `dbf:"NOTE"`
+	}
+	require.Nil(t, Marshal(table, row, item{Code: 123, Price: 4.25, Count: 7}))
+	require.Equal(t, table.FieldValue(row, 0), "123")
+	require.Equal(t, table.FieldValue(row, 1), "4.25")
+
+	require.Nil(t, table.SetFieldValueByName(row, "CODE", " 0042"))
+	require.Nil(t, table.SetFieldValueByName(row, "COUNT", "12.00"))
+	var unmarshalled item
+	require.Nil(t, Unmarshal(table, row, &unmarshalled))
+	require.Equal(t, unmarshalled.Code, 42)
+	require.Equal(t, unmarshalled.Price, 4.25)
+	require.Equal(t, unmarshalled.Count, uint16(12))
+	require.Equal(t, *unmarshalled.Note, 0, "blank values are zero")
+
+	require.Nil(t, table.SetFieldValueByName(row, "CODE", "12a"))
+	require.Nil(t, table.SetFieldValueByName(row, "PRICE", "n/a"))
+	require.Nil(t, table.SetFieldValueByName(row, "COUNT", "1.5"))
+	var failures ConversionErrors
+	err = Unmarshal(table, row, &unmarshalled)
+	require.True(t, errors.As(err, &failures))
+	require.Len(t, failures, 3)
+	require.EqualError(t, failures[0].Err, "invalid number \"12a\"")
+	require.EqualError(t, failures[1].Err, "invalid number \"n/a\"")
+	require.EqualError(t, failures[2].Err, "value 1.5 is not a whole number")
+}
+
+func TestMarshal_InvalidArguments(t *testing.T) {
+	table := createMarshalTable(t)
+	_, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	var customer marshalCustomer
+	require.EqualError(t, Unmarshal(table, 0, customer),
+		"Unmarshal requires a non-nil pointer to a struct, not godbf.marshalCustomer")
+	require.EqualError(t, Unmarshal(table, 1, &customer), "row 1 does not exist")
+	require.EqualError(t, UnmarshalAll(table, &[]int{}),
+		"UnmarshalAll requires a non-nil pointer to a slice of structs, not *[]int")
+	require.EqualError(t, Marshal(table, 0, 42), "Marshal requires a struct or a non-nil pointer to a struct, not int")
+
+	var missing struct {
+		City string `dbf:"CITY"`
+	}
+	require.EqualError(t, Unmarshal(table, 0, &missing), "Field name \"CITY\" does not exist")
+}
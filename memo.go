@@ -0,0 +1,447 @@
+package godbf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	memoBlockPointerLength       = 10
+	binaryMemoBlockPointerLength = 4
+	defaultMemoBlockSize         = 512
+	dBaseIVMemoBlockMarker       = 0x0008FFFF // FFh FFh 08h 00h, read little-endian
+	dBaseIVMemoBlockHeaderBytes  = 8
+)
+
+// memoFile keeps the content of a memo file (.DBT or .FPT) in memory, as its byte array encoding.
+// Memo values are addressed by the number of the block they start in, as stored in a table's memo fields.
+type memoFile interface {
+	// read returns the memo value starting at the given block.
+	read(block uint32) ([]byte, error)
+	// write stores the value in the memo file, returning the number of the block it starts in. The value replaces the
+	// one starting at the previous block, reusing its blocks if it fits in them, or is appended to the memo file
+	// otherwise, including when previous is 0. Binary values are flagged as such, where the memo file format supports
+	// it.
+	write(value []byte, isBinary bool, previous uint32) (block uint32, err error)
+	// bytes returns the complete memo file content.
+	bytes() []byte
+	// empty returns a memo file of the same kind, header and block size, holding no values.
+	empty() memoFile
+}
+
+// memoBlocks is the block structure shared by all memo file formats: a header starting with the number of the next
+// available block, followed by fixed-size blocks holding the memo values.
+type memoBlocks struct {
+	blockSize int
+	byteOrder binary.ByteOrder // of the next available block number
+	data      []byte
+}
+
+func (mb *memoBlocks) nextAvailableBlock() uint32 {
+	return mb.byteOrder.Uint32(mb.data[0:4])
+}
+
+func (mb *memoBlocks) setNextAvailableBlock(block uint32) {
+	mb.byteOrder.PutUint32(mb.data[0:4], block)
+}
+
+// content returns everything from the start of the given block up to the end of the memo file.
+func (mb *memoBlocks) content(block uint32) ([]byte, error) {
+	start := int(block) * mb.blockSize
+	if block == 0 || start >= len(mb.data) {
+		return nil, fmt.Errorf("memo block %d is out of range", block)
+	}
+	return mb.data[start:], nil
+}
+
+// appendBlocks stores the encoded value from the next available block onwards, padding it to whole blocks, and
+// returns the number of the block it starts in.
+func (mb *memoBlocks) appendBlocks(encoded []byte) (block uint32) {
+	block = mb.nextAvailableBlock()
+	start := int(block) * mb.blockSize
+	numberOfBlocks := (len(encoded) + mb.blockSize - 1) / mb.blockSize
+
+	if end := start + numberOfBlocks*mb.blockSize; len(mb.data) < end {
+		mb.data = append(mb.data, make([]byte, end-len(mb.data))...)
+	}
+	copy(mb.data[start:], encoded)
+
+	mb.setNextAvailableBlock(block + uint32(numberOfBlocks))
+	return
+}
+
+// writeBlocks stores the encoded value over the blocks of the previous value, whose encoding is previousLength bytes
+// long, if it needs no more of them, as dBase and FoxPro do. Otherwise, or if there is no previous value, the encoded
+// value is appended. The number of the block it starts in is returned.
+func (mb *memoBlocks) writeBlocks(encoded []byte, previous uint32, previousLength int) (block uint32) {
+	blocks := func(length int) int { return (length + mb.blockSize - 1) / mb.blockSize }
+	start := int(previous) * mb.blockSize
+	if previous == 0 || previousLength == 0 || blocks(len(encoded)) > blocks(previousLength) ||
+		start+len(encoded) > len(mb.data) {
+		return mb.appendBlocks(encoded)
+	}
+
+	copy(mb.data[start:], encoded)
+	// the end of the previous value is cleared, so that no stale content is left in the reused blocks
+	end := start + previousLength
+	if end > len(mb.data) {
+		end = len(mb.data)
+	}
+	if tail := start + len(encoded); tail < end {
+		clearBytes(mb.data[tail:end])
+	}
+	return previous
+}
+
+func (mb *memoBlocks) bytes() []byte {
+	return mb.data
+}
+
+// dbtMemo is a dBase III or dBase IV memo file.
+//
+// Block 0 is the header, starting with the number of the next available block (little-endian). dBase III stores
+// each value terminated by two end-of-file markers, while dBase IV prefixes each value with the FFh FFh 08h 00h
+// marker and the length of the value (including this 8-byte prefix).
+type dbtMemo struct {
+	memoBlocks
+	dBaseIV bool
+}
+
+func newDbtMemo(dBaseIV bool) *dbtMemo {
+	m := &dbtMemo{
+		memoBlocks: memoBlocks{
+			blockSize: defaultMemoBlockSize,
+			byteOrder: binary.LittleEndian,
+			data:      make([]byte, defaultMemoBlockSize),
+		},
+		dBaseIV: dBaseIV,
+	}
+
+	if dBaseIV {
+		binary.LittleEndian.PutUint16(m.data[20:22], uint16(m.blockSize))
+	} else {
+		m.data[16] = 0x03 // dBase III version number
+	}
+
+	m.setNextAvailableBlock(1)
+	return m
+}
+
+func loadDbtMemo(data []byte, dBaseIV bool) (m *dbtMemo, err error) {
+	if len(data) < 4 {
+		err = fmt.Errorf("memo file is %d bytes, too short to hold a header", len(data))
+		return
+	}
+
+	m = &dbtMemo{
+		memoBlocks: memoBlocks{
+			blockSize: defaultMemoBlockSize,
+			byteOrder: binary.LittleEndian,
+			data:      data,
+		},
+		dBaseIV: dBaseIV,
+	}
+
+	if dBaseIV && len(data) >= 22 {
+		if blockSize := int(binary.LittleEndian.Uint16(data[20:22])); blockSize > 0 {
+			m.blockSize = blockSize
+		}
+	}
+	return
+}
+
+func (m *dbtMemo) empty() memoFile {
+	e := &dbtMemo{
+		memoBlocks: memoBlocks{
+			blockSize: m.blockSize,
+			byteOrder: m.byteOrder,
+			data:      make([]byte, m.blockSize),
+		},
+		dBaseIV: m.dBaseIV,
+	}
+
+	copy(e.data, m.data)
+	e.setNextAvailableBlock(1)
+	return e
+}
+
+func (m *dbtMemo) read(block uint32) (value []byte, err error) {
+	var content []byte
+	if content, err = m.content(block); err != nil {
+		return
+	}
+
+	if m.dBaseIV && len(content) >= dBaseIVMemoBlockHeaderBytes &&
+		binary.LittleEndian.Uint32(content[0:4]) == dBaseIVMemoBlockMarker {
+		end := int(binary.LittleEndian.Uint32(content[4:8]))
+		if end < dBaseIVMemoBlockHeaderBytes || end > len(content) {
+			err = fmt.Errorf("memo block %d claims a length of %d bytes, but only %d are available",
+				block, end, len(content))
+			return
+		}
+		value = content[dBaseIVMemoBlockHeaderBytes:end]
+		return
+	}
+
+	if end := bytes.IndexByte(content, endOfFileMarker); end >= 0 {
+		content = content[:end]
+	}
+	value = content
+	return
+}
+
+func (m *dbtMemo) write(value []byte, isBinary bool, previous uint32) (block uint32, err error) {
+	var encoded []byte
+	if m.dBaseIV {
+		encoded = make([]byte, dBaseIVMemoBlockHeaderBytes, dBaseIVMemoBlockHeaderBytes+len(value))
+		binary.LittleEndian.PutUint32(encoded[0:4], dBaseIVMemoBlockMarker)
+		binary.LittleEndian.PutUint32(encoded[4:8], uint32(dBaseIVMemoBlockHeaderBytes+len(value)))
+		encoded = append(encoded, value...)
+	} else {
+		encoded = append(append(encoded, value...), endOfFileMarker, endOfFileMarker)
+	}
+
+	return m.writeBlocks(encoded, previous, m.storedLength(previous)), nil
+}
+
+// storedLength returns the number of bytes taken by the value starting at the given block, including its length
+// prefix or end-of-file markers, or 0 if it is unknown.
+func (m *dbtMemo) storedLength(block uint32) int {
+	content, err := m.content(block)
+	if err != nil {
+		return 0
+	}
+
+	if m.dBaseIV && len(content) >= dBaseIVMemoBlockHeaderBytes &&
+		binary.LittleEndian.Uint32(content[0:4]) == dBaseIVMemoBlockMarker {
+		if length := int(binary.LittleEndian.Uint32(content[4:8])); length <= len(content) {
+			return length
+		}
+		return 0
+	}
+
+	if end := bytes.IndexByte(content, endOfFileMarker); end >= 0 {
+		if end+2 > len(content) {
+			return len(content)
+		}
+		return end + 2
+	}
+	return 0
+}
+
+// memoSupport holds the memo file belonging to a DbfTable, if it has one.
+type memoSupport struct {
+	memo memoFile
+}
+
+// hasMemoFields returns true if any of the table's fields keep their values in a memo file.
+func (dt *DbfTable) hasMemoFields() bool {
+	for i := range dt.fields {
+		if dt.fields[i].fieldType.usesMemo() {
+			return true
+		}
+	}
+	return false
+}
+
+// MemoFieldValue returns the content of the memo for the record at the given row and field index as a string.
+// If the field is not a memo field, the table has no memo file, or the memo cannot be read, an error is returned.
+func (dt *DbfTable) MemoFieldValue(row int, fieldIndex int) (value string, err error) {
+	if err = dt.verifyMemoField(fieldIndex); err != nil {
+		return
+	}
+	return dt.memoValueFromRecord(dt.record(row), fieldIndex)
+}
+
+// MemoFieldValueByName returns the content of the memo for the record at the given row and field name as a string,
+// as per MemoFieldValue().
+func (dt *DbfTable) MemoFieldValueByName(row int, fieldName string) (value string, err error) {
+	if fieldIndex, entryFound := dt.fieldMap[fieldName]; entryFound {
+		return dt.MemoFieldValue(row, fieldIndex)
+	}
+	err = fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+	return
+}
+
+// BinaryMemoFieldValue returns the content of the memo for the record at the given row and field index as raw bytes,
+// without any character decoding applied. It is intended for General and Picture fields, but works with any memo
+// field. If the field is not a memo field, the table has no memo file, or the memo cannot be read, an error is returned.
+func (dt *DbfTable) BinaryMemoFieldValue(row int, fieldIndex int) (value []byte, err error) {
+	if err = dt.verifyMemoField(fieldIndex); err != nil {
+		return
+	}
+
+	var b []byte
+	if b, err = dt.memoBytesFromRecord(dt.record(row), fieldIndex); err != nil || b == nil {
+		return
+	}
+
+	value = make([]byte, len(b))
+	copy(value, b)
+	return
+}
+
+// SetBinaryMemoFieldValue writes the raw bytes supplied, without any character encoding applied, to the memo for the
+// record at the given row and field index. Values written to General and Picture fields are flagged as binary in
+// the memo file, where the format supports it.
+func (dt *DbfTable) SetBinaryMemoFieldValue(row int, fieldIndex int, value []byte) (err error) {
+	if err = dt.verifyMemoField(fieldIndex); err != nil {
+		return
+	}
+	if err = dt.setMemoBytesInRecord(dt.record(row), fieldIndex, value, dt.fields[fieldIndex].holdsBinaryData()); err == nil {
+		dt.clearNullFlag(dt.record(row), fieldIndex)
+	}
+	return
+}
+
+// BinaryMemoFieldValueByName returns the content of the memo for the record at the given row and field name as raw
+// bytes, as per BinaryMemoFieldValue().
+func (dt *DbfTable) BinaryMemoFieldValueByName(row int, fieldName string) (value []byte, err error) {
+	if fieldIndex, entryFound := dt.fieldMap[fieldName]; entryFound {
+		return dt.BinaryMemoFieldValue(row, fieldIndex)
+	}
+	err = fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+	return
+}
+
+// SetBinaryMemoFieldValueByName writes the raw bytes supplied to the memo for the record at the given row and field
+// name, as per SetBinaryMemoFieldValue().
+func (dt *DbfTable) SetBinaryMemoFieldValueByName(row int, fieldName string, value []byte) (err error) {
+	if fieldIndex, entryFound := dt.fieldMap[fieldName]; entryFound {
+		return dt.SetBinaryMemoFieldValue(row, fieldIndex, value)
+	}
+	return fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+}
+
+func (dt *DbfTable) verifyMemoField(fieldIndex int) error {
+	if !dt.fields[fieldIndex].fieldType.usesMemo() {
+		return fmt.Errorf("type of field \"%s\" is not Memo, General or Picture", dt.fields[fieldIndex].name)
+	}
+	return nil
+}
+
+// memoValueFromRecord resolves the memo block pointer for the field with the given index into the memo's content.
+// Binary memos are returned as is, while text memos are decoded.
+func (dt *DbfTable) memoValueFromRecord(record []byte, fieldIndex int) (value string, err error) {
+	var b []byte
+	if b, err = dt.memoBytesFromRecord(record, fieldIndex); err != nil || b == nil {
+		return
+	}
+
+	if !dt.fields[fieldIndex].holdsBinaryData() {
+		if b, err = dt.decodeBytes(b); err != nil {
+			return
+		}
+	}
+	return string(b), nil
+}
+
+// memoBytesFromRecord resolves the memo block pointer for the field with the given index into the memo's raw
+// content. Nil is returned when the field holds no memo.
+func (dt *DbfTable) memoBytesFromRecord(record []byte, fieldIndex int) (value []byte, err error) {
+	var block uint32
+	var ok bool
+	if block, ok, err = dt.memoBlockFromRecord(record, fieldIndex); err != nil || !ok {
+		return
+	}
+
+	if dt.memo == nil {
+		err = fmt.Errorf("field \"%s\" refers to memo block %d, but the table has no memo file",
+			dt.fields[fieldIndex].name, block)
+		return
+	}
+
+	return dt.memo.read(block)
+}
+
+// memoBlockFromRecord returns the memo block pointer stored for the field with the given index, ok being false when
+// the field holds no memo. Pointers are stored as 10 ASCII digits, except for Visual FoxPro, which stores them as
+// 4-byte little-endian integers.
+func (dt *DbfTable) memoBlockFromRecord(record []byte, fieldIndex int) (block uint32, ok bool, err error) {
+	offset := dt.fieldOffset(fieldIndex)
+	field := record[offset : offset+int(dt.fields[fieldIndex].length)]
+
+	if len(field) == binaryMemoBlockPointerLength {
+		block = binary.LittleEndian.Uint32(field)
+		return block, block != 0, nil
+	}
+
+	pointer := strings.TrimSpace(string(bytes.Trim(field, "\x00")))
+	if pointer == "" {
+		return
+	}
+
+	if _, err = fmt.Sscan(pointer, &block); err != nil {
+		err = fmt.Errorf("invalid memo block pointer \"%s\" in field \"%s\"", pointer, dt.fields[fieldIndex].name)
+		return
+	}
+	return block, block != 0, nil
+}
+
+// setMemoValueInRecord writes the value to the table's memo file, storing the block it starts in for the field with
+// the given index. Text memos are encoded, while binary memos are written as is.
+func (dt *DbfTable) setMemoValueInRecord(record []byte, fieldIndex int, value string) (err error) {
+	isBinary := dt.fields[fieldIndex].holdsBinaryData()
+
+	if !isBinary {
+		if value, err = dt.encodeString(value); err != nil {
+			return
+		}
+	}
+	return dt.setMemoBytesInRecord(record, fieldIndex, []byte(value), isBinary)
+}
+
+// setMemoBytesInRecord writes the raw value to the table's memo file, storing the block it starts in for the field
+// with the given index. The blocks of the field's previous memo are reused where the value fits in them. An empty
+// value clears the field without touching the memo file.
+func (dt *DbfTable) setMemoBytesInRecord(record []byte, fieldIndex int, value []byte, isBinary bool) (err error) {
+	offset := dt.fieldOffset(fieldIndex)
+	field := record[offset : offset+int(dt.fields[fieldIndex].length)]
+	// an unreadable pointer leaves no previous memo to reuse, the value being appended instead
+	previous, _, _ := dt.memoBlockFromRecord(record, fieldIndex)
+
+	if len(field) == binaryMemoBlockPointerLength {
+		binary.LittleEndian.PutUint32(field, 0)
+	} else {
+		fillFieldWithBlanks(field)
+	}
+
+	if len(value) == 0 {
+		return
+	}
+
+	if dt.memo == nil {
+		return fmt.Errorf("cannot set memo field \"%s\", the table has no memo file", dt.fields[fieldIndex].name)
+	}
+
+	var block uint32
+	if block, err = dt.memo.write(value, isBinary, previous); err != nil {
+		return
+	}
+
+	setMemoBlockInField(field, block)
+	return
+}
+
+// setMemoBlockInField stores the memo block pointer in the bytes of a memo field, as 10 ASCII digits, or as a 4-byte
+// little-endian integer for Visual FoxPro.
+func setMemoBlockInField(field []byte, block uint32) {
+	if len(field) == binaryMemoBlockPointerLength {
+		binary.LittleEndian.PutUint32(field, block)
+	} else {
+		copy(field, fmt.Sprintf("%*d", len(field), block))
+	}
+}
+
+// memoFileName derives the name of the memo file that accompanies the table file of the given name, keeping the
+// case of the table's file name extension.
+func memoFileName(fileName string, extension string) string {
+	ext := filepath.Ext(fileName)
+	if ext != "" && ext == strings.ToUpper(ext) {
+		extension = strings.ToUpper(extension)
+	}
+	return strings.TrimSuffix(fileName, ext) + "." + extension
+}
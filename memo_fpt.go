@@ -0,0 +1,123 @@
+package godbf
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	fptHeaderBytes       = 512
+	defaultFptBlockSize  = 64
+	fptBlockHeaderBytes  = 8
+	fptBlockTypePicture  = 0
+	fptBlockTypeText     = 1
+	fptBlockSizeOffset   = 6
+	fptMinimumHeaderSize = 8
+)
+
+// fptMemo is a FoxPro or Visual FoxPro memo file.
+//
+// The 512-byte header holds the number of the next available block and the block size, both big-endian. Each value
+// is prefixed by its block type (0 for picture/binary data, 1 for text) and its length, again both big-endian.
+type fptMemo struct {
+	memoBlocks
+}
+
+func newFptMemo() *fptMemo {
+	m := &fptMemo{
+		memoBlocks: memoBlocks{
+			blockSize: defaultFptBlockSize,
+			byteOrder: binary.BigEndian,
+			data:      make([]byte, fptHeaderBytes),
+		},
+	}
+
+	binary.BigEndian.PutUint16(m.data[fptBlockSizeOffset:fptBlockSizeOffset+2], uint16(m.blockSize))
+	m.setNextAvailableBlock(fptHeaderBytes / defaultFptBlockSize)
+	return m
+}
+
+func loadFptMemo(data []byte) (m *fptMemo, err error) {
+	if len(data) < fptMinimumHeaderSize {
+		err = fmt.Errorf("memo file is %d bytes, too short to hold a header", len(data))
+		return
+	}
+
+	blockSize := int(binary.BigEndian.Uint16(data[fptBlockSizeOffset : fptBlockSizeOffset+2]))
+	if blockSize == 0 {
+		err = fmt.Errorf("memo file declares a block size of 0")
+		return
+	}
+
+	m = &fptMemo{
+		memoBlocks: memoBlocks{
+			blockSize: blockSize,
+			byteOrder: binary.BigEndian,
+			data:      data,
+		},
+	}
+	return
+}
+
+func (m *fptMemo) empty() memoFile {
+	e := &fptMemo{
+		memoBlocks: memoBlocks{
+			blockSize: m.blockSize,
+			byteOrder: m.byteOrder,
+			data:      make([]byte, fptHeaderBytes),
+		},
+	}
+
+	copy(e.data, m.data)
+	e.setNextAvailableBlock(uint32((fptHeaderBytes + m.blockSize - 1) / m.blockSize))
+	return e
+}
+
+func (m *fptMemo) read(block uint32) (value []byte, err error) {
+	var content []byte
+	if content, err = m.content(block); err != nil {
+		return
+	}
+
+	if len(content) < fptBlockHeaderBytes {
+		err = fmt.Errorf("memo block %d is truncated", block)
+		return
+	}
+
+	length := int(binary.BigEndian.Uint32(content[4:8]))
+	if length > len(content)-fptBlockHeaderBytes {
+		err = fmt.Errorf("memo block %d claims a length of %d bytes, but only %d are available",
+			block, length, len(content)-fptBlockHeaderBytes)
+		return
+	}
+
+	value = content[fptBlockHeaderBytes : fptBlockHeaderBytes+length]
+	return
+}
+
+func (m *fptMemo) write(value []byte, isBinary bool, previous uint32) (block uint32, err error) {
+	blockType := uint32(fptBlockTypeText)
+	if isBinary {
+		blockType = fptBlockTypePicture
+	}
+
+	encoded := make([]byte, fptBlockHeaderBytes, fptBlockHeaderBytes+len(value))
+	binary.BigEndian.PutUint32(encoded[0:4], blockType)
+	binary.BigEndian.PutUint32(encoded[4:8], uint32(len(value)))
+	encoded = append(encoded, value...)
+
+	return m.writeBlocks(encoded, previous, m.storedLength(previous)), nil
+}
+
+// storedLength returns the number of bytes taken by the value starting at the given block, including its block
+// header, or 0 if it is unknown.
+func (m *fptMemo) storedLength(block uint32) int {
+	content, err := m.content(block)
+	if err != nil || len(content) < fptBlockHeaderBytes {
+		return 0
+	}
+	if length := fptBlockHeaderBytes + int(binary.BigEndian.Uint32(content[4:8])); length <= len(content) {
+		return length
+	}
+	return 0
+}
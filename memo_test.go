@@ -0,0 +1,207 @@
+package godbf
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func createMemoTable(t *testing.T, format Format) *DbfTable {
+	table := New(charmap.CodePage866, WithFormat(format))
+	return requireTestTable(t, table, 0, table.AddTextField("NAME", 10), table.AddMemoField("NOTES"))
+}
+
+func TestDbfTable_AddMemoField_SetsMemoSignature(t *testing.T) {
+	require.EqualValues(t, createMemoTable(t, DBaseIII).dataStore[0], 0x83)
+	require.EqualValues(t, createMemoTable(t, DBaseIV).dataStore[0], 0x8B)
+	require.EqualValues(t, New(nil).dataStore[0], 0x03)
+}
+
+func TestDbfTable_MemoField_SaveAndLoad(t *testing.T) {
+	for _, format := range []Format{DBaseIII, DBaseIV} {
+		table := createMemoTable(t, format)
+
+		longText := strings.Repeat("Привет, мир! ", 100)
+		values := []string{"short memo", "", longText, "after the long one"}
+		for _, value := range values {
+			row, err := table.AddNewRecord()
+			require.Nil(t, err)
+			require.Nil(t, table.SetFieldValueByName(row, "NAME", "name"))
+			require.Nil(t, table.SetFieldValueByName(row, "NOTES", value))
+		}
+
+		tempFilename := filepath.Join("testdata", "tempMemoTable.DBF")
+		require.Nil(t, table.Save(tempFilename, os.ModePerm))
+
+		_, err := os.Stat(filepath.Join("testdata", "tempMemoTable.DBT"))
+		require.Nil(t, err)
+
+		tableUnderTest, err := NewFromFile(tempFilename, charmap.CodePage866)
+		require.Nil(t, err)
+		require.Equal(t, tableUnderTest.Fields()[1].FieldType(), Memo)
+
+		for row, expected := range values {
+			value, err := tableUnderTest.FieldValueByName(row, "NOTES")
+			require.Nil(t, err)
+			require.Equal(t, value, expected)
+
+			value, err = tableUnderTest.MemoFieldValue(row, 1)
+			require.Nil(t, err)
+			require.Equal(t, value, expected)
+		}
+
+		// Updating a memo with a shorter value reuses its blocks.
+		require.Nil(t, tableUnderTest.SetFieldValueByName(0, "NOTES", "updated memo"))
+		value, err := tableUnderTest.FieldValueByName(0, "NOTES")
+		require.Nil(t, err)
+		require.Equal(t, value, "updated memo")
+		value, err = tableUnderTest.FieldValueByName(3, "NOTES")
+		require.Nil(t, err)
+		require.Equal(t, value, "after the long one")
+
+		require.Nil(t, os.Remove(tempFilename))
+		require.Nil(t, os.Remove(filepath.Join("testdata", "tempMemoTable.DBT")))
+	}
+}
+
+func TestDbfTable_MemoField_ReusesBlocks(t *testing.T) {
+	for _, format := range []Format{DBaseIII, DBaseIV, FoxPro} {
+		table := createMemoTable(t, format)
+		row, err := table.AddNewRecord()
+		require.Nil(t, err)
+		require.Nil(t, table.SetFieldValueByName(row, "NOTES", "first value"))
+
+		block, _, err := table.memoBlockFromRecord(table.record(row), 1)
+		require.Nil(t, err)
+		size := len(table.memo.bytes())
+
+		for _, value := range []string{"second, longer value", "x", "third value"} {
+			require.Nil(t, table.SetFieldValueByName(row, "NOTES", value))
+			updated, _, err := table.memoBlockFromRecord(table.record(row), 1)
+			require.Nil(t, err)
+			require.Equal(t, updated, block, format)
+			require.Equal(t, len(table.memo.bytes()), size, format)
+
+			memo, err := table.MemoFieldValue(row, 1)
+			require.Nil(t, err)
+			require.Equal(t, memo, value)
+		}
+
+		// A value needing more blocks than the previous one is appended, growing the memo file.
+		longText := strings.Repeat("long value ", 100)
+		require.Nil(t, table.SetFieldValueByName(row, "NOTES", longText))
+		updated, _, err := table.memoBlockFromRecord(table.record(row), 1)
+		require.Nil(t, err)
+		require.NotEqual(t, updated, block, format)
+		require.Greater(t, len(table.memo.bytes()), size, format)
+
+		memo, err := table.MemoFieldValue(row, 1)
+		require.Nil(t, err)
+		require.Equal(t, memo, longText)
+	}
+}
+
+func TestDbfTable_MemoField_MissingMemoFile(t *testing.T) {
+	table := createMemoTable(t, DBaseIII)
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Nil(t, table.SetFieldValueByName(row, "NOTES", "memo"))
+
+	tableUnderTest, err := NewFromByteArray(table.dataStore, charmap.CodePage866)
+	require.Nil(t, err)
+
+	value, err := tableUnderTest.FieldValueByName(row, "NOTES")
+	require.Nil(t, err)
+	require.Equal(t, value, "")
+
+	_, err = tableUnderTest.MemoFieldValue(row, 1)
+	require.NotNil(t, err)
+	t.Log(err)
+
+	err = tableUnderTest.SetFieldValueByName(row, "NOTES", "memo")
+	require.NotNil(t, err)
+	t.Log(err)
+}
+
+func TestDbfTable_MemoFieldValue_NotMemoField(t *testing.T) {
+	table := createMemoTable(t, DBaseIII)
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	_, err = table.MemoFieldValue(row, 0)
+	require.NotNil(t, err)
+	t.Log(err)
+}
+
+func TestMemoFileName(t *testing.T) {
+	require.Equal(t, memoFileName("data/table.dbf", "dbt"), "data/table.dbt")
+	require.Equal(t, memoFileName("data/TABLE.DBF", "dbt"), "data/TABLE.DBT")
+	require.Equal(t, memoFileName("table", "dbt"), "table.dbt")
+}
+
+func TestDbfTable_FoxProMemoFields_SaveAndLoad(t *testing.T) {
+	table := New(charmap.Windows1251, WithFormat(FoxPro))
+	require.Nil(t, table.AddMemoField("NOTES"))
+	require.Nil(t, table.AddGeneralField("OBJECT"))
+	require.Nil(t, table.AddPictureField("PHOTO"))
+	require.EqualValues(t, table.dataStore[0], 0xF5)
+
+	picture := []byte{0x89, 'P', 'N', 'G', 0x00, 0x1A, 0xFF}
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Nil(t, table.SetFieldValueByName(row, "NOTES", "Примечание"))
+	require.Nil(t, table.SetBinaryMemoFieldValue(row, 1, []byte("OLE")))
+	require.Nil(t, table.SetBinaryMemoFieldValue(row, 2, picture))
+
+	tempFilename := filepath.Join("testdata", "tempFoxProTable.dbf")
+	require.Nil(t, table.Save(tempFilename, os.ModePerm))
+	defer os.Remove(tempFilename)
+	defer os.Remove(filepath.Join("testdata", "tempFoxProTable.fpt"))
+
+	tableUnderTest, err := NewFromFile(tempFilename, charmap.Windows1251)
+	require.Nil(t, err)
+	require.Equal(t, tableUnderTest.format, FoxPro)
+	require.Equal(t, tableUnderTest.Fields()[1].FieldType(), General)
+	require.Equal(t, tableUnderTest.Fields()[2].FieldType(), Picture)
+
+	value, err := tableUnderTest.FieldValueByName(row, "NOTES")
+	require.Nil(t, err)
+	require.Equal(t, value, "Примечание")
+
+	b, err := tableUnderTest.BinaryMemoFieldValue(row, 2)
+	require.Nil(t, err)
+	require.Equal(t, b, picture)
+
+	b, err = tableUnderTest.BinaryMemoFieldValue(row, 0)
+	require.Nil(t, err)
+	require.Equal(t, b, []byte{0xcf, 0xf0, 0xe8, 0xec, 0xe5, 0xf7, 0xe0, 0xed, 0xe8, 0xe5})
+
+	// Each value is prefixed by its big-endian block type: 1 for text, 0 for binary.
+	memo := tableUnderTest.memo.(*fptMemo)
+	for fieldIndex, expectedType := range []byte{fptBlockTypeText, fptBlockTypePicture, fptBlockTypePicture} {
+		block, ok, err := tableUnderTest.memoBlockFromRecord(tableUnderTest.record(row), fieldIndex)
+		require.Nil(t, err)
+		require.True(t, ok)
+
+		content, err := memo.content(block)
+		require.Nil(t, err)
+		require.Equal(t, content[0:4], []byte{0, 0, 0, expectedType})
+	}
+}
+
+func TestDbfTable_MemoField_BinaryBlockPointer(t *testing.T) {
+	table := New(nil, WithFormat(FoxPro))
+	require.Nil(t, table.addField("NOTES", Memo, binaryMemoBlockPointerLength, notApplicable))
+
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Nil(t, table.SetFieldValue(row, 0, "stored via a 4-byte pointer"))
+
+	offset := table.recordOffset(row) + table.fieldOffset(0)
+	require.Equal(t, table.dataStore[offset:offset+4], []byte{fptHeaderBytes / defaultFptBlockSize, 0, 0, 0})
+	require.Equal(t, table.FieldValue(row, 0), "stored via a 4-byte pointer")
+}
@@ -0,0 +1,222 @@
+package godbf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	nullFlagsFieldName  = "_NullFlags"
+	nullFlagsFieldFlags = systemFieldFlag | binaryFieldFlag
+
+	// Visual FoxPro variable length types, which take a bit of _NullFlags each, whether nullable or not
+	varcharType   DbaseDataType = 'V'
+	varbinaryType DbaseDataType = 'Q'
+
+	uninitialisedLogical = '?'
+)
+
+// FieldIsNull returns true if the field of the given index holds NULL for the record at the given row. For nullable
+// Visual FoxPro fields this is recorded by the _NullFlags system field. Otherwise, blank Numeric, Float, Date and
+// Logical fields (and a Logical field holding '?') are NULL, as are dBase 7 binary fields that have not been set.
+// Values of other fields are never NULL; an empty Character field is an empty string.
+func (dt *DbfTable) FieldIsNull(row int, fieldIndex int) bool {
+	return dt.fieldIsNullInRecord(dt.record(row), fieldIndex)
+}
+
+// FieldIsNullByName returns true if the field of the given name holds NULL for the record at the given row, as per
+// FieldIsNull().
+func (dt *DbfTable) FieldIsNullByName(row int, fieldName string) (isNull bool, err error) {
+	if fieldIndex, entryFound := dt.fieldMap[fieldName]; entryFound {
+		return dt.FieldIsNull(row, fieldIndex), nil
+	}
+	err = fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+	return
+}
+
+// SetFieldNull sets the field of the given index to NULL for the record at the given row. For nullable Visual FoxPro
+// fields the flag in the _NullFlags system field is set and the field blanked, while Numeric, Float, Date and Logical
+// fields are blanked (Logical fields to '?'), and dBase 7 binary fields cleared. An error is returned for other
+// fields, which cannot hold NULL. Setting a value through any of the other setters clears the NULL flag again.
+func (dt *DbfTable) SetFieldNull(row int, fieldIndex int) error {
+	fd := &dt.fields[fieldIndex]
+	if fd.IsSystem() || fd.raw {
+		return fmt.Errorf("field \"%s\" cannot hold NULL values", fd.name)
+	}
+
+	record := dt.record(row)
+	offset := dt.fieldOffset(fieldIndex)
+	field := record[offset : offset+int(fd.length)]
+
+	if byteIndex, mask, ok := dt.nullFlag(fieldIndex); ok {
+		if fd.fieldType.isBinary() || len(field) == binaryMemoBlockPointerLength && fd.fieldType.usesMemo() {
+			clearBytes(field)
+		} else {
+			fillFieldWithBlanks(field)
+		}
+		dt.nullFlags(record)[byteIndex] |= mask
+		return nil
+	}
+
+	switch {
+	case fd.fieldType.oneOf(Numeric, Float, Date):
+		fillFieldWithBlanks(field)
+	case fd.fieldType == Logical:
+		field[0] = uninitialisedLogical
+	case dt.format == DBase7 && fd.fieldType.isBinary():
+		clearBytes(field)
+	default:
+		return fmt.Errorf("field \"%s\" cannot hold NULL values", fd.name)
+	}
+	return nil
+}
+
+// SetFieldNullByName sets the field of the given name to NULL for the record at the given row, as per SetFieldNull().
+func (dt *DbfTable) SetFieldNullByName(row int, fieldName string) error {
+	if fieldIndex, entryFound := dt.fieldMap[fieldName]; entryFound {
+		return dt.SetFieldNull(row, fieldIndex)
+	}
+	return fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+}
+
+// SetFieldNullable makes the field of the given name able to hold NULL values, while a Visual FoxPro table is being
+// created. The _NullFlags system field, which records which values are NULL, is added after all other fields, or
+// resized to match.
+func (dt *DbfTable) SetFieldNullable(fieldName string) error {
+	if dt.format != VisualFoxPro {
+		return errors.New("only Visual FoxPro tables have nullable fields")
+	}
+	if dt.schemaLocked {
+		return errors.New("Once you start entering data to the dbase table or open an existing dbase file, altering dbase table schema is not allowed!")
+	}
+
+	fieldIndex, entryFound := dt.fieldMap[fieldName]
+	if !entryFound {
+		return fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+	}
+	fd := &dt.fields[fieldIndex]
+	if fd.IsSystem() {
+		return fmt.Errorf("field \"%s\" is a system field", fieldName)
+	}
+
+	fd.flags |= nullableFieldFlag
+	fd.fieldStore[fieldFlagsIndex] = fd.flags
+	return dt.resizeNullFlagsField()
+}
+
+// resizeNullFlagsField replaces the _NullFlags system field with one holding a bit for each field that needs one.
+func (dt *DbfTable) resizeNullFlagsField() error {
+	if fieldIndex, ok := dt.nullFlagsFieldIndex(); ok {
+		dt.fields = append(dt.fields[:fieldIndex], dt.fields[fieldIndex+1:]...)
+		delete(dt.fieldMap, nullFlagsFieldName)
+		dt.updateHeader()
+	}
+
+	bits := dt.nullFlagBits(len(dt.fields))
+	if bits == 0 {
+		return nil
+	}
+
+	if err := dt.addRawField(nullFlagsFieldName, NullFlags, byte((bits+7)/8), NullFlags.decimalCountNotApplicable()); err != nil {
+		return err
+	}
+	fd := &dt.fields[len(dt.fields)-1]
+	fd.flags = nullFlagsFieldFlags
+	fd.fieldStore[fieldFlagsIndex] = fd.flags
+	dt.updateHeader()
+	return nil
+}
+
+// fieldIsNullInRecord returns true if the field with the given index holds NULL in the bytes of a single record.
+func (dt *DbfTable) fieldIsNullInRecord(record []byte, fieldIndex int) bool {
+	if byteIndex, mask, ok := dt.nullFlag(fieldIndex); ok {
+		return dt.nullFlags(record)[byteIndex]&mask != 0
+	}
+
+	fd := &dt.fields[fieldIndex]
+	offset := dt.fieldOffset(fieldIndex)
+	field := record[offset : offset+int(fd.length)]
+
+	switch {
+	case fd.fieldType.oneOf(Numeric, Float, Date):
+		value := strings.Trim(string(field), " \x00")
+		return value == "" || fd.fieldType == Date && value == blankDate
+	case fd.fieldType == Logical:
+		return field[0] == uninitialisedLogical || field[0] == blank || field[0] == null
+	case dt.format == DBase7 && fd.fieldType.isBinary():
+		return isZeroBytes(field)
+	}
+	return false
+}
+
+// clearNullFlag clears the NULL flag of the field with the given index in the bytes of a single record, if it has one.
+func (dt *DbfTable) clearNullFlag(record []byte, fieldIndex int) {
+	if byteIndex, mask, ok := dt.nullFlag(fieldIndex); ok {
+		dt.nullFlags(record)[byteIndex] &^= mask
+	}
+}
+
+// nullFlag locates the bit of the _NullFlags system field that flags the field with the given index as NULL. Each
+// variable length field takes a bit first, then each nullable field takes the next, in the order of the fields.
+// False is returned if the field is not nullable, or if the table has no _NullFlags field.
+func (dt *DbfTable) nullFlag(fieldIndex int) (byteIndex int, mask byte, ok bool) {
+	if !dt.fields[fieldIndex].IsNullable() {
+		return
+	}
+
+	nullFlagsIndex, found := dt.nullFlagsFieldIndex()
+	if !found {
+		return
+	}
+
+	bit := dt.nullFlagBits(fieldIndex)
+	if dt.fields[fieldIndex].fieldType.oneOf(varcharType, varbinaryType) {
+		bit++
+	}
+	if bit >= int(dt.fields[nullFlagsIndex].length)*8 {
+		return
+	}
+	return bit / 8, 1 << uint(bit%8), true
+}
+
+// nullFlagBits counts the bits of the _NullFlags system field taken by the fields before the given index.
+func (dt *DbfTable) nullFlagBits(fieldIndex int) (bits int) {
+	for i := 0; i < fieldIndex; i++ {
+		if dt.fields[i].IsSystem() {
+			continue
+		}
+		if dt.fields[i].fieldType.oneOf(varcharType, varbinaryType) {
+			bits++
+		}
+		if dt.fields[i].IsNullable() {
+			bits++
+		}
+	}
+	return
+}
+
+// nullFlags returns the bytes of the _NullFlags system field in the bytes of a single record.
+func (dt *DbfTable) nullFlags(record []byte) []byte {
+	fieldIndex, _ := dt.nullFlagsFieldIndex()
+	offset := dt.fieldOffset(fieldIndex)
+	return record[offset : offset+int(dt.fields[fieldIndex].length)]
+}
+
+// nullFlagsFieldIndex returns the index of the _NullFlags system field, if the table has one.
+func (dt *DbfTable) nullFlagsFieldIndex() (int, bool) {
+	for i := range dt.fields {
+		if dt.fields[i].fieldType == NullFlags && dt.fields[i].IsSystem() {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// keepsNullFlagsFieldLast returns true if a field of the given type added to the table is to be placed before the
+// _NullFlags system field, rather than after it.
+func (dt *DbfTable) keepsNullFlagsFieldLast(fieldType DbaseDataType) bool {
+	n := len(dt.fields)
+	return dt.createdFromScratch && fieldType != NullFlags && n > 0 &&
+		dt.fields[n-1].fieldType == NullFlags && dt.fields[n-1].IsSystem()
+}
@@ -0,0 +1,127 @@
+package godbf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDbfTable_FieldIsNull_BlankMeansNull(t *testing.T) {
+	table := New(nil)
+	require.Nil(t, table.AddNumberField("N", 5, 1))
+	require.Nil(t, table.AddFloatField("F", 5, 1))
+	require.Nil(t, table.AddDateField("D"))
+	require.Nil(t, table.AddBooleanField("L"))
+	require.Nil(t, table.AddTextField("C", 5))
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	for i := 0; i < 4; i++ {
+		require.True(t, table.FieldIsNull(row, i), table.fields[i].name)
+	}
+	require.False(t, table.FieldIsNull(row, 4), "an empty Character field is not NULL")
+
+	for i, value := range []string{"0.0", "0", "20240102", "F"} {
+		require.Nil(t, table.SetFieldValue(row, i, value))
+		require.False(t, table.FieldIsNull(row, i), table.fields[i].name)
+		require.Nil(t, table.SetFieldNull(row, i))
+		require.True(t, table.FieldIsNull(row, i), table.fields[i].name)
+	}
+	require.Equal(t, "?", table.FieldValue(row, 3))
+
+	require.EqualError(t, table.SetFieldNull(row, 4), "field \"C\" cannot hold NULL values")
+
+	isNull, err := table.FieldIsNullByName(row, "N")
+	require.Nil(t, err)
+	require.True(t, isNull)
+	_, err = table.FieldIsNullByName(row, "MISSING")
+	require.NotNil(t, err)
+	require.NotNil(t, table.SetFieldNullByName(row, "MISSING"))
+}
+
+func TestDbfTable_FieldIsNull_DBase7Binary(t *testing.T) {
+	table := New(nil, WithFormat(DBase7))
+	require.Nil(t, table.AddIntegerField("I"))
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	require.True(t, table.FieldIsNull(row, 0))
+	require.Nil(t, table.SetFieldValue(row, 0, "0"))
+	require.False(t, table.FieldIsNull(row, 0))
+	require.Nil(t, table.SetFieldNull(row, 0))
+	require.True(t, table.FieldIsNull(row, 0))
+}
+
+func newNullableTestTable(t *testing.T) *DbfTable {
+	table := New(nil, WithFormat(VisualFoxPro))
+	return requireTestTable(t, table, 0,
+		table.AddTextField("NAME", 5),
+		table.AddIntegerField("ID"),
+		table.SetFieldNullable("NAME"),
+		table.AddDateField("DUE"),
+		table.SetFieldNullable("DUE"))
+}
+
+func TestDbfTable_SetFieldNullable_KeepsNullFlagsLastAndHidden(t *testing.T) {
+	table := newNullableTestTable(t)
+
+	require.Equal(t, []string{"NAME", "ID", "DUE"}, table.FieldNames())
+	require.Len(t, table.Fields(), 3)
+	require.Len(t, table.AllFields(), 4)
+
+	nullFlags := table.AllFields()[3]
+	require.Equal(t, nullFlagsFieldName, nullFlags.Name())
+	require.Equal(t, NullFlags, nullFlags.FieldType())
+	require.EqualValues(t, 1, nullFlags.Length())
+	require.True(t, nullFlags.IsSystem())
+	require.True(t, table.Fields()[0].IsNullable())
+	require.False(t, table.Fields()[1].IsNullable())
+
+	require.NotNil(t, table.SetFieldNullable("MISSING"))
+	require.NotNil(t, table.SetFieldNullable(nullFlagsFieldName))
+	require.NotNil(t, New(nil).SetFieldNullable("NAME"))
+}
+
+func TestDbfTable_FieldIsNull_VisualFoxProNullFlags(t *testing.T) {
+	table := newNullableTestTable(t)
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	require.False(t, table.FieldIsNull(row, 0))
+	require.False(t, table.FieldIsNull(row, 2), "a blank nullable field is not NULL unless flagged")
+
+	require.Nil(t, table.SetFieldNullByName(row, "DUE"))
+	require.True(t, table.FieldIsNull(row, 2))
+	require.False(t, table.FieldIsNull(row, 0))
+	require.Equal(t, []byte{0x02}, table.RawFieldValue(row, 3))
+
+	require.Nil(t, table.SetFieldNull(row, 0))
+	require.Equal(t, []byte{0x03}, table.RawFieldValue(row, 3))
+
+	require.EqualError(t, table.SetFieldNull(row, 1), "field \"ID\" cannot hold NULL values")
+	require.NotNil(t, table.SetFieldNull(row, 3))
+
+	require.Nil(t, table.SetTimeFieldValue(row, 2, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+	require.False(t, table.FieldIsNull(row, 2))
+	require.Nil(t, table.SetFieldValue(row, 0, "x"))
+	require.False(t, table.FieldIsNull(row, 0))
+	require.Equal(t, []byte{0x00}, table.RawFieldValue(row, 3))
+
+	require.Equal(t, []string{"x", "20240102"}, []string{table.GetRowAsSlice(row)[0], table.GetRowAsSlice(row)[2]})
+	require.Len(t, table.GetRowAsSlice(row), 3)
+}
+
+func TestDbfTable_FieldIsNull_VisualFoxProSaveAndLoad(t *testing.T) {
+	table := newNullableTestTable(t)
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Nil(t, table.SetFieldNull(row, 2))
+
+	loaded, err := NewFromByteArray(table.dataStore, nil)
+	require.Nil(t, err)
+	require.Equal(t, []string{"NAME", "ID", "DUE"}, loaded.FieldNames())
+	require.True(t, loaded.AllFields()[3].IsSystem())
+	require.True(t, loaded.FieldIsNull(row, 2))
+	require.False(t, loaded.FieldIsNull(row, 0))
+}
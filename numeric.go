@@ -0,0 +1,259 @@
+package godbf
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// RoundingMode determines how values with more decimal places than a Numeric or Float field holds are rounded.
+type RoundingMode int
+
+const (
+	// RoundHalfAwayFromZero rounds to the nearest value, with halves rounded away from zero, as dBase does.
+	RoundHalfAwayFromZero RoundingMode = iota
+	// RoundHalfEven rounds to the nearest value, with halves rounded to the nearest even digit (banker's rounding).
+	RoundHalfEven
+	// RoundTowardZero drops any decimal places that do not fit.
+	RoundTowardZero
+)
+
+// overflowFill is written across a Numeric or Float field whose value does not fit, as dBase does.
+const overflowFill = '*'
+
+// NumericOption configures how a value is formatted into a Numeric or Float field.
+type NumericOption func(f *numericFormat)
+
+// WithRounding has values rounded to the field's decimal places according to the mode given, rather than the default
+// of RoundHalfAwayFromZero.
+func WithRounding(mode RoundingMode) NumericOption {
+	return func(f *numericFormat) {
+		f.rounding = mode
+	}
+}
+
+// WithOverflowFill has values that do not fit the field's length written as a field filled with '*', as dBase does,
+// rather than returning an error.
+func WithOverflowFill() NumericOption {
+	return func(f *numericFormat) {
+		f.fillOnOverflow = true
+	}
+}
+
+// numericFormat describes how values are formatted into a Numeric or Float field.
+type numericFormat struct {
+	length         int
+	decimalPlaces  int
+	rounding       RoundingMode
+	fillOnOverflow bool
+}
+
+// SetFloat64FieldValueByName sets the value of a Numeric or Float field given row number and name provided, formatted
+// with exactly the field's decimal places. The value is rounded from its shortest decimal representation, so that
+// 1.005 rounds to 1.01 with 2 decimal places, as it would when written by hand.
+func (dt *DbfTable) SetFloat64FieldValueByName(row int, fieldName string, value float64, options ...NumericOption) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Errorf("value %v for field \"%s\" is not a number", value, fieldName)
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, 64))
+	return dt.setNumericFieldValueByName(row, fieldName, r, options)
+}
+
+// SetInt64FieldValueByName sets the value of a Numeric or Float field given row number and name provided, formatted
+// with exactly the field's decimal places.
+func (dt *DbfTable) SetInt64FieldValueByName(row int, fieldName string, value int64, options ...NumericOption) error {
+	return dt.setNumericFieldValueByName(row, fieldName, new(big.Rat).SetInt64(value), options)
+}
+
+// SetDecimalFieldValueByName sets the value of a Numeric or Float field given row number and name provided, from a
+// decimal string such as "-1234.5678", formatted with exactly the field's decimal places. Unlike
+// SetFieldValueByName(), the value is parsed, so that it is rounded rather than cut, and so that invalid values are
+// rejected.
+func (dt *DbfTable) SetDecimalFieldValueByName(row int, fieldName string, value string, options ...NumericOption) error {
+	r, err := parseDecimal(value)
+	if err != nil {
+		return fmt.Errorf("%v for field \"%s\"", err, fieldName)
+	}
+	return dt.setNumericFieldValueByName(row, fieldName, r, options)
+}
+
+// RatFieldValueByName returns the value of a Numeric or Float field given row number and name provided as an exact
+// decimal, without the loss of precision of Float64FieldValueByName(). A blank field is returned as nil. An error is
+// returned if the field is not a Numeric or Float field, or if it does not hold a valid decimal, such as a field
+// filled with '*' after an overflow.
+func (dt *DbfTable) RatFieldValueByName(row int, fieldName string) (value *big.Rat, err error) {
+	fieldIndex, entryFound := dt.fieldMap[fieldName]
+	if !entryFound {
+		return nil, fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+	}
+
+	var field []byte
+	if field, err = dt.numericField(row, fieldIndex); err != nil {
+		return
+	}
+
+	s := decimalText(field)
+	if s == "" {
+		return
+	}
+	if value, err = parseDecimal(s); err != nil {
+		err = fmt.Errorf("%v in field \"%s\"", err, fieldName)
+	}
+	return
+}
+
+// DecimalInt64FieldValueByName returns the value of a Numeric or Float field given row number and name provided as
+// an int64, parsed as a decimal integer. Unlike Int64FieldValueByName(), leading zeros such as those of "00012" do not
+// have the value read as octal. An error is returned if the field is not a Numeric or Float field, or if it does not
+// hold an integer, such as a blank field.
+func (dt *DbfTable) DecimalInt64FieldValueByName(row int, fieldName string) (value int64, err error) {
+	fieldIndex, entryFound := dt.fieldMap[fieldName]
+	if !entryFound {
+		return 0, fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+	}
+
+	var field []byte
+	if field, err = dt.numericField(row, fieldIndex); err != nil {
+		return
+	}
+	s := decimalText(field)
+	if value, err = strconv.ParseInt(s, 10, 64); err != nil {
+		err = fmt.Errorf("invalid integer value \"%s\" in field \"%s\"", s, fieldName)
+	}
+	return
+}
+
+// SetRatFieldValueByName sets the value of a Numeric or Float field given row number and name provided from an exact
+// decimal, formatted with exactly the field's decimal places as per DecimalPlacesInField(). A field already holding
+// the value is left as it is, so that values read with RatFieldValueByName() are written back byte-for-byte as they
+// were stored, such as "1.5E+3" or "-0.00". A nil value sets the field to NULL, as SetFieldNull() does.
+func (dt *DbfTable) SetRatFieldValueByName(row int, fieldName string, value *big.Rat, options ...NumericOption) error {
+	fieldIndex, entryFound := dt.fieldMap[fieldName]
+	if !entryFound {
+		return fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+	}
+	field, err := dt.numericField(row, fieldIndex)
+	if err != nil {
+		return err
+	}
+
+	if value == nil {
+		return dt.SetFieldNull(row, fieldIndex)
+	}
+	if stored, err := parseDecimal(decimalText(field)); err == nil && stored.Cmp(value) == 0 {
+		return nil
+	}
+	return dt.setNumericFieldValue(row, fieldIndex, value, options)
+}
+
+// decimalText returns the text of a Numeric or Float field without its padding.
+func decimalText(field []byte) string {
+	return strings.TrimSpace(strings.Trim(string(field), "\x00"))
+}
+
+// parseDecimal parses a decimal string, with an optional exponent, into an exact rational number.
+func parseDecimal(value string) (*big.Rat, error) {
+	value = strings.TrimSpace(value)
+	r, ok := new(big.Rat).SetString(value)
+	if !ok || strings.Contains(value, "/") {
+		return nil, fmt.Errorf("invalid decimal value \"%s\"", value)
+	}
+	return r, nil
+}
+
+func (dt *DbfTable) setNumericFieldValueByName(row int, fieldName string, value *big.Rat, options []NumericOption) error {
+	if fieldIndex, entryFound := dt.fieldMap[fieldName]; entryFound {
+		return dt.setNumericFieldValue(row, fieldIndex, value, options)
+	}
+	return fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+}
+
+// setNumericFieldValue formats the value into the Numeric or Float field with the given index, for the record at the
+// given row.
+func (dt *DbfTable) setNumericFieldValue(row int, fieldIndex int, value *big.Rat, options []NumericOption) error {
+	field, err := dt.numericField(row, fieldIndex)
+	if err != nil {
+		return err
+	}
+
+	f := numericFormat{length: len(field), decimalPlaces: int(dt.fields[fieldIndex].decimalPlaces)}
+	for _, option := range options {
+		option(&f)
+	}
+
+	s := f.format(value)
+	if len(s) > f.length {
+		if !f.fillOnOverflow {
+			return fmt.Errorf("value %s does not fit field \"%s\" of length %d", s, dt.fields[fieldIndex].name, f.length)
+		}
+		for i := range field {
+			field[i] = overflowFill
+		}
+	} else {
+		fillFieldWithBlanks(field)
+		copy(field[len(field)-len(s):], s)
+	}
+	dt.clearNullFlag(dt.record(row), fieldIndex)
+	return nil
+}
+
+// numericField returns the bytes of the Numeric or Float field with the given index, for the record at the given row.
+func (dt *DbfTable) numericField(row int, fieldIndex int) (field []byte, err error) {
+	if !dt.fields[fieldIndex].fieldType.oneOf(Numeric, Float) {
+		err = fmt.Errorf("type of field \"%s\" is not Numeric or Float", dt.fields[fieldIndex].name)
+		return
+	}
+
+	offset := dt.fieldOffset(fieldIndex)
+	return dt.record(row)[offset : offset+int(dt.fields[fieldIndex].length)], nil
+}
+
+// format rounds the value to the decimal places of the field, returning its text representation, which may be longer
+// than the field.
+func (f numericFormat) format(value *big.Rat) string {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(f.decimalPlaces)), nil)
+	scaled := f.round(new(big.Rat).Mul(value, new(big.Rat).SetInt(scale)))
+	return formatScaled(scaled, f.decimalPlaces)
+}
+
+// formatScaled formats an integer holding a number scaled by 10^decimalPlaces, such as 12345 with 2 decimal places
+// as "123.45".
+func formatScaled(scaled *big.Int, decimalPlaces int) string {
+	sign := ""
+	if scaled.Sign() < 0 {
+		sign = "-"
+	}
+
+	digits := new(big.Int).Abs(scaled).String()
+	if decimalPlaces == 0 {
+		return sign + digits
+	}
+	if len(digits) <= decimalPlaces {
+		digits = strings.Repeat("0", decimalPlaces-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-decimalPlaces] + "." + digits[len(digits)-decimalPlaces:]
+}
+
+// round rounds the rational number to an integer according to the rounding mode.
+func (f numericFormat) round(r *big.Rat) *big.Int {
+	switch f.rounding {
+	case RoundTowardZero:
+		return new(big.Int).Quo(r.Num(), r.Denom())
+	case RoundHalfEven:
+		quotient, remainder := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+		doubled := new(big.Int).Abs(remainder)
+		switch doubled.Lsh(doubled, 1).Cmp(r.Denom()) {
+		case 1:
+			quotient.Add(quotient, big.NewInt(int64(r.Sign())))
+		case 0:
+			if quotient.Bit(0) == 1 {
+				quotient.Add(quotient, big.NewInt(int64(r.Sign())))
+			}
+		}
+		return quotient
+	default:
+		return roundRatHalfAwayFromZero(r)
+	}
+}
@@ -0,0 +1,185 @@
+package godbf
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newNumericTestTable(t *testing.T) *DbfTable {
+	table := New(nil)
+	return requireTestTable(t, table, 1,
+		table.AddNumberField("AMOUNT", 8, 2), table.AddFloatField("RATIO", 6, 0), table.AddTextField("NAME", 4))
+}
+
+func TestDbfTable_SetFloat64FieldValueByName(t *testing.T) {
+	table := newNumericTestTable(t)
+
+	for value, expected := range map[float64]string{
+		1.005:    "    1.01",
+		-1.005:   "   -1.01",
+		12345.67: "12345.67",
+		0.001:    "    0.00",
+		-0.001:   "    0.00",
+		7:        "    7.00",
+	} {
+		require.Nil(t, table.SetFloat64FieldValueByName(0, "AMOUNT", value))
+		require.Equal(t, []byte(expected), table.RawFieldValue(0, 0), "%v", value)
+	}
+
+	require.NotNil(t, table.SetFloat64FieldValueByName(0, "AMOUNT", math.NaN()))
+	require.NotNil(t, table.SetFloat64FieldValueByName(0, "AMOUNT", math.Inf(1)))
+}
+
+func TestDbfTable_SetInt64FieldValueByName(t *testing.T) {
+	table := newNumericTestTable(t)
+
+	require.Nil(t, table.SetInt64FieldValueByName(0, "AMOUNT", -42))
+	require.Equal(t, []byte("  -42.00"), table.RawFieldValue(0, 0))
+	require.Nil(t, table.SetInt64FieldValueByName(0, "RATIO", 123456))
+	require.Equal(t, []byte("123456"), table.RawFieldValue(0, 1))
+}
+
+func TestDbfTable_SetDecimalFieldValueByName_Rounding(t *testing.T) {
+	table := newNumericTestTable(t)
+
+	for _, c := range []struct {
+		value    string
+		mode     RoundingMode
+		expected string
+	}{
+		{"2.345", RoundHalfAwayFromZero, "    2.35"},
+		{"2.345", RoundHalfEven, "    2.34"},
+		{"2.355", RoundHalfEven, "    2.36"},
+		{"-2.345", RoundHalfEven, "   -2.34"},
+		{"2.3451", RoundHalfEven, "    2.35"},
+		{"2.349", RoundTowardZero, "    2.34"},
+		{"-2.349", RoundTowardZero, "   -2.34"},
+		{"1e2", RoundHalfAwayFromZero, "  100.00"},
+	} {
+		require.Nil(t, table.SetDecimalFieldValueByName(0, "AMOUNT", c.value, WithRounding(c.mode)))
+		require.Equal(t, []byte(c.expected), table.RawFieldValue(0, 0), "%s %d", c.value, c.mode)
+	}
+
+	require.NotNil(t, table.SetDecimalFieldValueByName(0, "AMOUNT", "1/3"))
+	require.NotNil(t, table.SetDecimalFieldValueByName(0, "AMOUNT", "abc"))
+}
+
+func TestDbfTable_SetDecimalFieldValueByName_Overflow(t *testing.T) {
+	table := newNumericTestTable(t)
+	require.Nil(t, table.SetDecimalFieldValueByName(0, "AMOUNT", "1"))
+
+	err := table.SetDecimalFieldValueByName(0, "AMOUNT", "123456.7")
+	require.EqualError(t, err, "value 123456.70 does not fit field \"AMOUNT\" of length 8")
+	require.Equal(t, []byte("    1.00"), table.RawFieldValue(0, 0), "field is left unchanged")
+
+	require.NotNil(t, table.SetDecimalFieldValueByName(0, "AMOUNT", "99999.995"), "overflows once rounded")
+
+	require.Nil(t, table.SetDecimalFieldValueByName(0, "AMOUNT", "123456.7", WithOverflowFill()))
+	require.Equal(t, []byte("********"), table.RawFieldValue(0, 0))
+}
+
+func TestDbfTable_SetNumericFieldValueByName_Errors(t *testing.T) {
+	table := newNumericTestTable(t)
+
+	require.EqualError(t, table.SetInt64FieldValueByName(0, "NAME", 1), "type of field \"NAME\" is not Numeric or Float")
+	require.EqualError(t, table.SetInt64FieldValueByName(0, "MISSING", 1), "Field name \"MISSING\" does not exist")
+}
+
+func TestDbfTable_DecimalInt64FieldValueByName(t *testing.T) {
+	table := newNumericTestTable(t)
+
+	for stored, expected := range map[string]int64{"00012": 12, "09": 9, "+7": 7, " -42": -42} {
+		require.Nil(t, table.SetFieldValueByName(0, "RATIO", stored))
+		value, err := table.DecimalInt64FieldValueByName(0, "RATIO")
+		require.Nil(t, err, stored)
+		require.Equal(t, expected, value, stored)
+	}
+
+	require.Nil(t, table.SetFieldValueByName(0, "RATIO", ""))
+	_, err := table.DecimalInt64FieldValueByName(0, "RATIO")
+	require.EqualError(t, err, "invalid integer value \"\" in field \"RATIO\"")
+	_, err = table.DecimalInt64FieldValueByName(0, "NAME")
+	require.EqualError(t, err, "type of field \"NAME\" is not Numeric or Float")
+	_, err = table.DecimalInt64FieldValueByName(0, "MISSING")
+	require.EqualError(t, err, "Field name \"MISSING\" does not exist")
+}
+
+func TestDbfTable_RatFieldValueByName_RoundTripsExactly(t *testing.T) {
+	table := New(nil)
+	require.Nil(t, table.AddNumberField("TOTAL", 21, 2))
+	require.Nil(t, table.AddNumberField("COUNT", 20, 0))
+	require.Nil(t, table.AddFloatField("PRICE", 10, 2))
+	_, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	for _, stored := range []struct{ fieldName, value string }{
+		{"TOTAL", "-1234567890123456.78"},
+		{"COUNT", "12345678901234567890"},
+		{"PRICE", "1.5E+3"},
+		{"PRICE", "1.5"},
+		{"PRICE", "+1.00"},
+		{"PRICE", "-0.00"},
+	} {
+		fieldName := stored.fieldName
+		require.Nil(t, table.SetFieldValueByName(0, fieldName, stored.value))
+		before, err := table.RawFieldValueByName(0, fieldName)
+		require.Nil(t, err)
+
+		value, err := table.RatFieldValueByName(0, fieldName)
+		require.Nil(t, err)
+		require.Nil(t, table.SetRatFieldValueByName(0, fieldName, value))
+
+		after, err := table.RawFieldValueByName(0, fieldName)
+		require.Nil(t, err)
+		require.Equal(t, before, after, stored.value)
+	}
+
+	value, err := table.RatFieldValueByName(0, "TOTAL")
+	require.Nil(t, err)
+	require.Equal(t, "-1234567890123456.78", value.FloatString(2))
+}
+
+func TestDbfTable_RatFieldValueByName_Blank(t *testing.T) {
+	table := newNumericTestTable(t)
+
+	value, err := table.RatFieldValueByName(0, "AMOUNT")
+	require.Nil(t, err)
+	require.Nil(t, value)
+
+	require.Nil(t, table.SetRatFieldValueByName(0, "AMOUNT", big.NewRat(1, 8)))
+	require.Equal(t, []byte("    0.13"), table.RawFieldValue(0, 0))
+	require.Nil(t, table.SetRatFieldValueByName(0, "AMOUNT", nil))
+	require.Equal(t, []byte("        "), table.RawFieldValue(0, 0))
+}
+
+func TestDbfTable_SetRatFieldValueByName_Null(t *testing.T) {
+	table := New(nil, WithFormat(VisualFoxPro))
+	require.Nil(t, table.AddNumberField("AMOUNT", 8, 2))
+	require.Nil(t, table.SetFieldNullable("AMOUNT"))
+	_, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	require.Nil(t, table.SetRatFieldValueByName(0, "AMOUNT", big.NewRat(5, 2)))
+	require.False(t, table.FieldIsNull(0, 0))
+	require.Nil(t, table.SetRatFieldValueByName(0, "AMOUNT", nil))
+	require.True(t, table.FieldIsNull(0, 0))
+	require.Equal(t, []byte("        "), table.RawFieldValue(0, 0))
+}
+
+func TestDbfTable_RatFieldValueByName_Errors(t *testing.T) {
+	table := newNumericTestTable(t)
+
+	require.Nil(t, table.SetDecimalFieldValueByName(0, "AMOUNT", "123456.7", WithOverflowFill()))
+	_, err := table.RatFieldValueByName(0, "AMOUNT")
+	require.EqualError(t, err, "invalid decimal value \"********\" in field \"AMOUNT\"")
+
+	_, err = table.RatFieldValueByName(0, "NAME")
+	require.NotNil(t, err)
+	_, err = table.RatFieldValueByName(0, "MISSING")
+	require.NotNil(t, err)
+	require.NotNil(t, table.SetRatFieldValueByName(0, "MISSING", nil))
+	require.NotNil(t, table.SetRatFieldValueByName(0, "NAME", nil))
+}
@@ -0,0 +1,192 @@
+package godbf
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+
+	"golang.org/x/text/encoding"
+)
+
+// RandomAccessTable provides read access to the records of a dBase table through an io.ReaderAt. Only the header is
+// kept in memory; each record is read from the underlying source on demand, making it suitable for looking up a handful
+// of rows in a large table.
+type RandomAccessTable struct {
+	table  *DbfTable // schema of the table being read, its dataStore holds the header only
+	source io.ReaderAt
+	size   int64
+
+	cache *recordCache
+}
+
+// OpenReaderAt creates a RandomAccessTable over the first size bytes of the supplied io.ReaderAt, expecting the
+// supplied encoding. Records are not cached unless SetRecordCacheSize() is called.
+func OpenReaderAt(r io.ReaderAt, size int64, enc encoding.Encoding) (table *RandomAccessTable, err error) {
+	var header *DbfTable
+	if header, err = readHeader(io.NewSectionReader(r, 0, size), enc); err != nil {
+		return
+	}
+
+	expectedSize := int64(header.numberOfBytesInHeader) + int64(header.numberOfRecords)*int64(header.lengthOfEachRecord)
+	if size < expectedSize {
+		err = fmt.Errorf("encoded content is %d bytes, but header expected %d", size, expectedSize)
+		return
+	}
+
+	table = &RandomAccessTable{
+		table:  header,
+		source: r,
+		size:   size,
+	}
+	return
+}
+
+// SetRecordCacheSize keeps up to the given number of most recently read records in memory, so that repeated access to
+// the same rows does not hit the underlying io.ReaderAt. A size of 0 or less disables caching.
+func (rt *RandomAccessTable) SetRecordCacheSize(size int) {
+	if size <= 0 {
+		rt.cache = nil
+		return
+	}
+	rt.cache = newRecordCache(size)
+}
+
+// Fields return the fields of the table as a slice
+func (rt *RandomAccessTable) Fields() []FieldDescriptor {
+	return rt.table.Fields()
+}
+
+// FieldNames return the names of fields in the table as a slice
+func (rt *RandomAccessTable) FieldNames() []string {
+	return rt.table.FieldNames()
+}
+
+// NumberOfRecords returns the number of records in the table
+func (rt *RandomAccessTable) NumberOfRecords() int {
+	return rt.table.NumberOfRecords()
+}
+
+// HasRecord returns true if the table has a record with the given number otherwise, false is returned.
+func (rt *RandomAccessTable) HasRecord(recordNumber int) bool {
+	return recordNumber >= 0 && recordNumber < rt.NumberOfRecords()
+}
+
+// FieldValue returns the content for the record at the given row and field index as a string
+// If the row or field index is invalid, or the record cannot be read, an error is returned.
+func (rt *RandomAccessTable) FieldValue(row int, fieldIndex int) (value string, err error) {
+	if fieldIndex < 0 || fieldIndex >= len(rt.Fields()) {
+		err = fmt.Errorf("field index %d is out of range", fieldIndex)
+		return
+	}
+
+	var record []byte
+	if record, err = rt.record(row); err != nil {
+		return
+	}
+	return rt.table.fieldValueFromRecord(record, fieldIndex), nil
+}
+
+// FieldValueByName returns the value of a field given row number and name provided
+func (rt *RandomAccessTable) FieldValueByName(row int, fieldName string) (value string, err error) {
+	if fieldIndex, entryFound := rt.table.fieldMap[fieldName]; entryFound {
+		return rt.FieldValue(row, fieldIndex)
+	}
+	err = fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+	return
+}
+
+// RowIsDeleted returns whether a row has marked as deleted
+func (rt *RandomAccessTable) RowIsDeleted(row int) (deleted bool, err error) {
+	var record []byte
+	if record, err = rt.record(row); err != nil {
+		return
+	}
+	return recordIsMarkedDeleted(record), nil
+}
+
+// GetRowAsSlice return the record values for the row specified as a string slice
+func (rt *RandomAccessTable) GetRowAsSlice(row int) (s []string, err error) {
+	var record []byte
+	if record, err = rt.record(row); err != nil {
+		return
+	}
+
+	s = make([]string, len(rt.Fields()))
+	for i := 0; i < len(rt.Fields()); i++ {
+		s[i] = rt.table.fieldValueFromRecord(record, i)
+	}
+	return
+}
+
+// record returns the bytes of the record at the given row, reading it from the cache when possible.
+func (rt *RandomAccessTable) record(row int) (record []byte, err error) {
+	if !rt.HasRecord(row) {
+		err = fmt.Errorf("row %d is out of range, table has %d records", row, rt.NumberOfRecords())
+		return
+	}
+
+	if rt.cache != nil {
+		if record = rt.cache.get(row); record != nil {
+			return
+		}
+	}
+
+	record = make([]byte, rt.table.lengthOfEachRecord)
+	// a full read may still report io.EOF, where the record ends the source without an end-of-file marker
+	if n, readErr := rt.source.ReadAt(record, int64(rt.table.recordOffset(row))); n < len(record) {
+		if readErr == io.EOF {
+			readErr = io.ErrUnexpectedEOF
+		}
+		return nil, readErr
+	}
+
+	if rt.cache != nil {
+		rt.cache.put(row, record)
+	}
+	return
+}
+
+// recordCache is a least-recently-used cache of records, keyed by row number.
+type recordCache struct {
+	capacity int
+	order    *list.List // front is the most recently used entry
+	entries  map[int]*list.Element
+}
+
+type recordCacheEntry struct {
+	row    int
+	record []byte
+}
+
+func newRecordCache(capacity int) *recordCache {
+	return &recordCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[int]*list.Element),
+	}
+}
+
+func (rc *recordCache) get(row int) []byte {
+	element, found := rc.entries[row]
+	if !found {
+		return nil
+	}
+	rc.order.MoveToFront(element)
+	return element.Value.(*recordCacheEntry).record
+}
+
+func (rc *recordCache) put(row int, record []byte) {
+	if element, found := rc.entries[row]; found {
+		element.Value.(*recordCacheEntry).record = record
+		rc.order.MoveToFront(element)
+		return
+	}
+
+	rc.entries[row] = rc.order.PushFront(&recordCacheEntry{row: row, record: record})
+
+	if rc.order.Len() > rc.capacity {
+		oldest := rc.order.Back()
+		rc.order.Remove(oldest)
+		delete(rc.entries, oldest.Value.(*recordCacheEntry).row)
+	}
+}
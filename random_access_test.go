@@ -0,0 +1,136 @@
+package godbf
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingReaderAt counts the number of reads made against the wrapped io.ReaderAt.
+type countingReaderAt struct {
+	*bytes.Reader
+	reads int
+}
+
+func (cr *countingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	cr.reads++
+	return cr.Reader.ReadAt(p, off)
+}
+
+// eofReaderAt returns io.EOF along with reads reaching the end of its data, as io.ReaderAt permits.
+type eofReaderAt struct {
+	data []byte
+}
+
+func (er eofReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(er.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, er.data[off:])
+	if off+int64(n) == int64(len(er.data)) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func TestOpenReaderAt_ValidFile_RecordsAreCorrect(t *testing.T) {
+	file, err := os.Open(validTestFile)
+	require.Nil(t, err)
+	defer file.Close()
+
+	info, err := file.Stat()
+	require.Nil(t, err)
+
+	tableUnderTest, err := OpenReaderAt(file, info.Size(), nil)
+	require.Nil(t, err)
+	require.Equal(t, tableUnderTest.NumberOfRecords(), 3)
+	require.Equal(t, tableUnderTest.FieldNames(), []string{"TESTBOOL", "TESTTEXT", "TESTDATE", "TESTNUM", "TESTFLOAT"})
+
+	row, err := tableUnderTest.GetRowAsSlice(2)
+	require.Nil(t, err)
+	require.Equal(t, row, []string{"T", "test2", "20180103", "44", "44.03000"})
+
+	value, err := tableUnderTest.FieldValueByName(1, "TESTTEXT")
+	require.Nil(t, err)
+	require.Equal(t, value, "test1")
+
+	deleted, err := tableUnderTest.RowIsDeleted(0)
+	require.Nil(t, err)
+	require.False(t, deleted)
+}
+
+func TestOpenReaderAt_OutOfRange_Errors(t *testing.T) {
+	rawFileBytes, err := ioutil.ReadFile(validTestFile)
+	require.Nil(t, err)
+
+	tableUnderTest, err := OpenReaderAt(bytes.NewReader(rawFileBytes), int64(len(rawFileBytes)), nil)
+	require.Nil(t, err)
+
+	_, err = tableUnderTest.FieldValue(3, 0)
+	require.NotNil(t, err)
+	t.Log(err)
+
+	_, err = tableUnderTest.FieldValue(0, 5)
+	require.NotNil(t, err)
+	t.Log(err)
+
+	_, err = tableUnderTest.FieldValueByName(0, "missingField")
+	require.NotNil(t, err)
+	t.Log(err)
+}
+
+func TestOpenReaderAt_SizeSmallerThanHeaderExpects_Errors(t *testing.T) {
+	rawFileBytes, err := ioutil.ReadFile(validTestFile)
+	require.Nil(t, err)
+
+	_, err = OpenReaderAt(bytes.NewReader(rawFileBytes), int64(len(rawFileBytes)-1), nil)
+	require.NotNil(t, err)
+	t.Log(err)
+}
+
+func TestRandomAccessTable_RecordCache(t *testing.T) {
+	rawFileBytes, err := ioutil.ReadFile(validTestFile)
+	require.Nil(t, err)
+
+	source := &countingReaderAt{Reader: bytes.NewReader(rawFileBytes)}
+	tableUnderTest, err := OpenReaderAt(source, int64(len(rawFileBytes)), nil)
+	require.Nil(t, err)
+	tableUnderTest.SetRecordCacheSize(2)
+
+	readsAfterHeader := source.reads
+	for _, row := range []int{0, 1, 0, 1} {
+		_, err = tableUnderTest.GetRowAsSlice(row)
+		require.Nil(t, err)
+	}
+	require.Equal(t, source.reads-readsAfterHeader, 2)
+
+	// Row 2 evicts row 0, the least recently used.
+	for _, row := range []int{2, 1, 0} {
+		_, err = tableUnderTest.GetRowAsSlice(row)
+		require.Nil(t, err)
+	}
+	require.Equal(t, source.reads-readsAfterHeader, 4)
+}
+
+func TestOpenReaderAt_LastRecordReadWithEOF(t *testing.T) {
+	rawFileBytes, err := ioutil.ReadFile(validTestFile)
+	require.Nil(t, err)
+	// without the end-of-file marker, the file ends exactly at its last record
+	rawFileBytes = bytes.TrimSuffix(rawFileBytes, []byte{endOfFileMarker})
+
+	tableUnderTest, err := OpenReaderAt(eofReaderAt{data: rawFileBytes}, int64(len(rawFileBytes)), nil)
+	require.Nil(t, err)
+	row, err := tableUnderTest.GetRowAsSlice(2)
+	require.Nil(t, err)
+	require.Equal(t, row, []string{"T", "test2", "20180103", "44", "44.03000"})
+
+	truncated := rawFileBytes[:len(rawFileBytes)-1]
+	tableUnderTest, err = OpenReaderAt(eofReaderAt{data: truncated}, int64(len(rawFileBytes)), nil)
+	require.Nil(t, err)
+	_, err = tableUnderTest.GetRowAsSlice(2)
+	require.Equal(t, err, io.ErrUnexpectedEOF)
+}
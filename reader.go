@@ -0,0 +1,121 @@
+package godbf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/text/encoding"
+)
+
+// Reader reads the records of a dBase table one at a time from an io.Reader, holding no more than a single record
+// in memory. Use it instead of NewFromFile or NewFromByteArray when a table is too large to load as a whole.
+//
+//	reader, err := godbf.NewReader(file, charmap.CodePage866)
+//	for reader.Next() {
+//	  value, err := reader.FieldValueByName("SOME_COLUMN_ID")
+//	}
+//	err = reader.Err()
+type Reader struct {
+	table  *DbfTable // schema of the table being read, its dataStore holds the header only
+	source io.Reader
+	record []byte
+	row    int
+	err    error
+}
+
+// NewReader creates a Reader, reading the table header from the supplied io.Reader, expecting the supplied encoding.
+func NewReader(r io.Reader, enc encoding.Encoding) (reader *Reader, err error) {
+	var table *DbfTable
+	if table, err = readHeader(r, enc); err != nil {
+		return
+	}
+
+	reader = &Reader{
+		table:  table,
+		source: r,
+		record: make([]byte, table.lengthOfEachRecord),
+		row:    -1,
+	}
+	return
+}
+
+// Next advances the reader to the next record, which is then available through the other Reader methods.
+// It returns false when there are no more records, or an error occurred. Err() tells these two cases apart.
+func (r *Reader) Next() bool {
+	if r.err != nil || r.row+1 >= r.table.NumberOfRecords() {
+		return false
+	}
+
+	n, err := io.ReadFull(r.source, r.record)
+	if n > 0 && r.record[0] == endOfFileMarker {
+		r.err = fmt.Errorf("end-of-file marker found after %d records, but header expected %d",
+			r.row+1, r.table.NumberOfRecords())
+		return false
+	}
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		r.err = err
+		return false
+	}
+
+	r.row++
+	return true
+}
+
+// Err returns the first error encountered by Next(), or nil if all records were read successfully.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Row returns the 0-based row number of the current record.
+func (r *Reader) Row() int {
+	return r.row
+}
+
+// Fields return the fields of the table as a slice
+func (r *Reader) Fields() []FieldDescriptor {
+	return r.table.Fields()
+}
+
+// FieldNames return the names of fields in the table as a slice
+func (r *Reader) FieldNames() []string {
+	return r.table.FieldNames()
+}
+
+// NumberOfRecords returns the number of records in the table, as declared in its header
+func (r *Reader) NumberOfRecords() int {
+	return r.table.NumberOfRecords()
+}
+
+// FieldValue returns the content of the current record for the given field index as a string
+func (r *Reader) FieldValue(fieldIndex int) string {
+	return r.table.fieldValueFromRecord(r.record, fieldIndex)
+}
+
+// FieldValueByName returns the content of the current record for the field name provided
+func (r *Reader) FieldValueByName(fieldName string) (value string, err error) {
+	if fieldIndex, entryFound := r.table.fieldMap[fieldName]; entryFound {
+		return r.FieldValue(fieldIndex), err
+	}
+	err = fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+	return
+}
+
+// RowIsDeleted returns whether the current record has been marked as deleted
+func (r *Reader) RowIsDeleted() bool {
+	return recordIsMarkedDeleted(r.record)
+}
+
+// GetRowAsSlice return the values of the current record as a string slice
+func (r *Reader) GetRowAsSlice() []string {
+	s := make([]string, len(r.Fields()))
+
+	for i := 0; i < len(r.Fields()); i++ {
+		s[i] = r.FieldValue(i)
+	}
+
+	return s
+}
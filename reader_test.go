@@ -0,0 +1,114 @@
+package godbf
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader_ValidFile_RecordsAreCorrect(t *testing.T) {
+	file, err := os.Open(validTestFile)
+	require.Nil(t, err)
+	defer file.Close()
+
+	reader, err := NewReader(file, nil)
+	require.Nil(t, err)
+	require.Equal(t, reader.NumberOfRecords(), 3)
+	require.Equal(t, reader.FieldNames(), []string{"TESTBOOL", "TESTTEXT", "TESTDATE", "TESTNUM", "TESTFLOAT"})
+
+	expectedRows := [][]string{
+		{"T", "test0", "20180101", "42", "42.01000"},
+		{"F", "test1", "20180102", "43", "43.02000"},
+		{"T", "test2", "20180103", "44", "44.03000"},
+	}
+
+	for i := 0; reader.Next(); i++ {
+		require.Equal(t, reader.Row(), i)
+		require.False(t, reader.RowIsDeleted())
+		require.Equal(t, reader.GetRowAsSlice(), expectedRows[i])
+
+		value, err := reader.FieldValueByName("TESTTEXT")
+		require.Nil(t, err)
+		require.Equal(t, value, expectedRows[i][1])
+	}
+	require.Nil(t, reader.Err())
+	require.Equal(t, reader.Row(), 2)
+}
+
+func TestReader_MatchesTableLoadedFromBytes(t *testing.T) {
+	table := New(nil)
+	require.Nil(t, table.AddTextField("NAME", 10))
+	require.Nil(t, table.AddNumberField("AMOUNT", 8, 2))
+
+	for _, name := range []string{"first", "second"} {
+		row, err := table.AddNewRecord()
+		require.Nil(t, err)
+		require.Nil(t, table.SetFieldValueByName(row, "NAME", name))
+		require.Nil(t, table.SetFieldValueByName(row, "AMOUNT", "12.50"))
+	}
+	table.dataStore[table.recordOffset(1)] = recordIsDeleted
+
+	reader, err := NewReader(bytes.NewReader(table.dataStore), nil)
+	require.Nil(t, err)
+
+	for reader.Next() {
+		require.Equal(t, reader.GetRowAsSlice(), table.GetRowAsSlice(reader.Row()))
+		require.Equal(t, reader.RowIsDeleted(), table.RowIsDeleted(reader.Row()))
+	}
+	require.Nil(t, reader.Err())
+	require.Equal(t, reader.Row(), 1)
+}
+
+func TestReader_FieldValueByName_NonExistentField(t *testing.T) {
+	file, err := os.Open(validTestFile)
+	require.Nil(t, err)
+	defer file.Close()
+
+	reader, err := NewReader(file, nil)
+	require.Nil(t, err)
+	require.True(t, reader.Next())
+
+	_, err = reader.FieldValueByName("missingField")
+	require.NotNil(t, err)
+	t.Log(err)
+}
+
+func TestReader_LessThanActualRecords_StopsAtHeaderCount(t *testing.T) {
+	file, err := os.Open(lessThanActualRecordsFile)
+	require.Nil(t, err)
+	defer file.Close()
+
+	reader, err := NewReader(file, nil)
+	require.Nil(t, err)
+
+	for reader.Next() {
+	}
+	require.Nil(t, reader.Err())
+	require.Equal(t, reader.Row(), reader.NumberOfRecords()-1)
+}
+
+func TestReader_TruncatedRecord_Errors(t *testing.T) {
+	rawFileBytes, err := ioutil.ReadFile(validTestFile)
+	require.Nil(t, err)
+
+	reader, err := NewReader(bytes.NewReader(rawFileBytes[:len(rawFileBytes)-10]), nil)
+	require.Nil(t, err)
+
+	for reader.Next() {
+	}
+	require.NotNil(t, reader.Err())
+	require.Equal(t, reader.Row(), 1)
+	t.Log(reader.Err())
+}
+
+func TestReader_TruncatedHeader_Errors(t *testing.T) {
+	rawFileBytes, err := ioutil.ReadFile(validTestFile)
+	require.Nil(t, err)
+
+	_, err = NewReader(bytes.NewReader(rawFileBytes[:40]), nil)
+	require.NotNil(t, err)
+	t.Log(err)
+}
@@ -0,0 +1,98 @@
+package godbf
+
+import "fmt"
+
+// DeleteRecord marks the record at the given row as deleted. The record is kept, and can be recalled with
+// RecallRecord(), until the table is packed with Pack().
+func (dt *DbfTable) DeleteRecord(row int) error {
+	return dt.setRecordDeletionFlag(row, recordIsDeleted)
+}
+
+// RecallRecord clears the deletion mark of the record at the given row, as set by DeleteRecord().
+func (dt *DbfTable) RecallRecord(row int) error {
+	return dt.setRecordDeletionFlag(row, recordIsActive)
+}
+
+func (dt *DbfTable) setRecordDeletionFlag(row int, flag byte) error {
+	if row < 0 || !dt.HasRecord(row) {
+		return fmt.Errorf("row %d does not exist", row)
+	}
+	dt.record(row)[recordDeletionFlagIndex] = flag
+	return nil
+}
+
+// Pack physically removes the records marked as deleted, so that the rows of the remaining records are renumbered
+// from 0. The memo file, if any, is rebuilt to hold only the memos of the remaining records. If a memo cannot be read,
+// an error is returned and the table is left unchanged. Index files are not maintained, and need to be rebuilt.
+func (dt *DbfTable) Pack() (err error) {
+	var memo memoFile
+	if dt.memo != nil {
+		memo = dt.memo.empty()
+	}
+
+	records := make([]byte, 0, len(dt.dataStore)-int(dt.numberOfBytesInHeader))
+	var numberOfRecords uint32
+
+	for row := 0; row < dt.NumberOfRecords(); row++ {
+		if dt.RowIsDeleted(row) {
+			continue
+		}
+
+		start := len(records)
+		records = append(records, dt.record(row)...)
+		if memo != nil {
+			if err = dt.copyMemos(records[start:], memo); err != nil {
+				return
+			}
+		}
+		numberOfRecords++
+	}
+
+	dt.dataStore = append(dt.dataStore[:dt.numberOfBytesInHeader:dt.numberOfBytesInHeader], records...)
+	dt.setNumberOfRecords(numberOfRecords)
+	if memo != nil {
+		dt.memo = memo
+	}
+	return
+}
+
+// copyMemos copies the memos of the record to the memo file given, updating the record's memo block pointers to
+// match.
+func (dt *DbfTable) copyMemos(record []byte, memo memoFile) (err error) {
+	for i := range dt.fields {
+		if !dt.fields[i].fieldType.usesMemo() {
+			continue
+		}
+
+		var value []byte
+		if value, err = dt.memoBytesFromRecord(record, i); err != nil {
+			return
+		}
+
+		var block uint32
+		if len(value) > 0 {
+			if block, err = memo.write(value, dt.fields[i].holdsBinaryData(), 0); err != nil {
+				return
+			}
+		}
+
+		offset := dt.fieldOffset(i)
+		field := record[offset : offset+int(dt.fields[i].length)]
+		if block == 0 && len(field) != binaryMemoBlockPointerLength {
+			fillFieldWithBlanks(field)
+		} else {
+			setMemoBlockInField(field, block)
+		}
+	}
+	return
+}
+
+// Zap removes all records from the table, along with all memos from its memo file, if any. The schema of the table
+// is kept. Index files are not maintained, and need to be rebuilt.
+func (dt *DbfTable) Zap() {
+	dt.dataStore = dt.dataStore[:dt.numberOfBytesInHeader:dt.numberOfBytesInHeader]
+	dt.setNumberOfRecords(0)
+	if dt.memo != nil {
+		dt.memo = dt.memo.empty()
+	}
+}
@@ -0,0 +1,136 @@
+package godbf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestDbfTable_DeleteRecord_And_RecallRecord(t *testing.T) {
+	table := New(nil)
+	require.Nil(t, table.AddTextField("NAME", 10))
+	_, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	require.Nil(t, table.DeleteRecord(0))
+	require.True(t, table.RowIsDeleted(0))
+	require.Nil(t, table.RecallRecord(0))
+	require.False(t, table.RowIsDeleted(0))
+
+	require.EqualError(t, table.DeleteRecord(1), "row 1 does not exist")
+	require.EqualError(t, table.RecallRecord(-1), "row -1 does not exist")
+}
+
+func TestDbfTable_Pack_RemovesDeletedRecordsAndTheirMemos(t *testing.T) {
+	for _, format := range []Format{DBaseIII, DBaseIV, FoxPro, VisualFoxPro} {
+		table := createMemoTable(t, format)
+		names := []string{"first", "second", "third", "fourth", "fifth"}
+		memos := []string{"first memo", "second memo", "", "fourth memo", "fifth memo"}
+		for i := range names {
+			row, err := table.AddNewRecord()
+			require.Nil(t, err)
+			require.Nil(t, table.SetFieldValueByName(row, "NAME", names[i]))
+			require.Nil(t, table.SetFieldValueByName(row, "NOTES", memos[i]))
+		}
+		memoLength := len(table.memo.bytes())
+
+		require.Nil(t, table.DeleteRecord(1))
+		require.Nil(t, table.DeleteRecord(4))
+		require.Nil(t, table.Pack())
+
+		require.Equal(t, table.NumberOfRecords(), 3)
+		require.Equal(t, table.dataStore[4:8], []byte{3, 0, 0, 0})
+		require.Len(t, table.dataStore, int(table.numberOfBytesInHeader)+3*int(table.lengthOfEachRecord))
+		require.Less(t, len(table.memo.bytes()), memoLength)
+
+		tempFilename := filepath.Join("testdata", "tempPackedTable.dbf")
+		require.Nil(t, table.Save(tempFilename, os.ModePerm))
+		tableUnderTest, err := NewFromFile(tempFilename, charmap.CodePage866)
+		require.Nil(t, os.Remove(tempFilename))
+		require.Nil(t, os.Remove(memoFileName(tempFilename, format.memoFileExtension())))
+		require.Nil(t, err)
+
+		for row, i := range []int{0, 2, 3} {
+			require.False(t, tableUnderTest.RowIsDeleted(row))
+			value, err := tableUnderTest.FieldValueByName(row, "NAME")
+			require.Nil(t, err)
+			require.Equal(t, value, names[i])
+			value, err = tableUnderTest.FieldValueByName(row, "NOTES")
+			require.Nil(t, err)
+			require.Equal(t, value, memos[i])
+		}
+	}
+}
+
+func TestDbfTable_Pack_KeepsBinaryMemos(t *testing.T) {
+	table := New(nil, WithFormat(FoxPro))
+	require.Nil(t, table.AddPictureField("PHOTO"))
+	picture := []byte{0x89, 'P', 'N', 'G', 0x00, 0x1A, 0xFF}
+	for i := 0; i < 2; i++ {
+		row, err := table.AddNewRecord()
+		require.Nil(t, err)
+		require.Nil(t, table.SetBinaryMemoFieldValue(row, 0, picture[i:]))
+	}
+
+	require.Nil(t, table.DeleteRecord(0))
+	require.Nil(t, table.Pack())
+
+	b, err := table.BinaryMemoFieldValue(0, 0)
+	require.Nil(t, err)
+	require.Equal(t, b, picture[1:])
+
+	block, ok, err := table.memoBlockFromRecord(table.record(0), 0)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.EqualValues(t, block, fptHeaderBytes/defaultFptBlockSize)
+	content, err := table.memo.(*fptMemo).content(block)
+	require.Nil(t, err)
+	require.Equal(t, content[0:4], []byte{0, 0, 0, fptBlockTypePicture})
+}
+
+func TestDbfTable_Pack_UnreadableMemoLeavesTableUnchanged(t *testing.T) {
+	table := createMemoTable(t, DBaseIII)
+	for i := 0; i < 2; i++ {
+		row, err := table.AddNewRecord()
+		require.Nil(t, err)
+		require.Nil(t, table.SetFieldValueByName(row, "NOTES", "memo"))
+	}
+	require.Nil(t, table.DeleteRecord(0))
+
+	offset := table.recordOffset(1) + table.fieldOffset(1)
+	copy(table.dataStore[offset:], "      9999")
+	dataStore := append([]byte(nil), table.dataStore...)
+
+	require.NotNil(t, table.Pack())
+	require.Equal(t, table.dataStore, dataStore)
+	require.Equal(t, table.NumberOfRecords(), 2)
+}
+
+func TestDbfTable_Zap(t *testing.T) {
+	for _, format := range []Format{DBaseIV, VisualFoxPro} {
+		table := createMemoTable(t, format)
+		emptyMemo := append([]byte(nil), table.memo.bytes()...)
+		for i := 0; i < 3; i++ {
+			row, err := table.AddNewRecord()
+			require.Nil(t, err)
+			require.Nil(t, table.SetFieldValueByName(row, "NOTES", "memo"))
+		}
+
+		table.Zap()
+		require.Equal(t, table.NumberOfRecords(), 0)
+		require.Equal(t, table.dataStore[4:8], []byte{0, 0, 0, 0})
+		require.Len(t, table.dataStore, int(table.numberOfBytesInHeader))
+		require.Equal(t, table.memo.bytes(), emptyMemo)
+
+		row, err := table.AddNewRecord()
+		require.Nil(t, err)
+		require.Equal(t, row, 0)
+		require.Nil(t, table.SetFieldValueByName(row, "NOTES", "after zap"))
+		value, err := table.FieldValueByName(row, "NOTES")
+		require.Nil(t, err)
+		require.Equal(t, value, "after zap")
+	}
+}
@@ -0,0 +1,202 @@
+package sqldriver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NovikovRoman/godbf"
+)
+
+// truth is the result of a condition, which is unknown when NULL values are compared.
+type truth int
+
+const (
+	isFalse truth = iota
+	isTrue
+	isUnknown
+)
+
+// timeLayouts are the layouts of strings that are compared with, and stored in, Date and DateTime fields.
+var timeLayouts = []string{"2006-01-02", "2006-01-02 15:04:05", "2006-01-02T15:04:05", "20060102", godbf.DateTimeLayout}
+
+func truthOf(b bool) truth {
+	if b {
+		return isTrue
+	}
+	return isFalse
+}
+
+func (t truth) not() truth {
+	switch t {
+	case isTrue:
+		return isFalse
+	case isFalse:
+		return isTrue
+	}
+	return isUnknown
+}
+
+func (t truth) and(other truth) truth {
+	switch {
+	case t == isFalse || other == isFalse:
+		return isFalse
+	case t == isUnknown || other == isUnknown:
+		return isUnknown
+	}
+	return isTrue
+}
+
+func (t truth) or(other truth) truth {
+	switch {
+	case t == isTrue || other == isTrue:
+		return isTrue
+	case t == isUnknown || other == isUnknown:
+		return isUnknown
+	}
+	return isFalse
+}
+
+// compareWith compares the values with the operator given. The result is unknown if either value is NULL.
+func compareWith(operator string, left, right interface{}) (truth, error) {
+	if left == nil || right == nil {
+		return isUnknown, nil
+	}
+
+	order, err := compareValues(left, right)
+	if err != nil {
+		return isUnknown, err
+	}
+
+	switch operator {
+	case "=":
+		return truthOf(order == 0), nil
+	case "<>":
+		return truthOf(order != 0), nil
+	case "<":
+		return truthOf(order < 0), nil
+	case "<=":
+		return truthOf(order <= 0), nil
+	case ">":
+		return truthOf(order > 0), nil
+	case ">=":
+		return truthOf(order >= 0), nil
+	}
+	return isUnknown, fmt.Errorf("unknown operator \"%s\"", operator)
+}
+
+// compareValues returns -1, 0 or 1 as a is less than, equal to or greater than b. Numbers are compared with numbers,
+// and with strings that hold numbers. Times are compared with times, and with strings in one of the timeLayouts.
+// Strings and bools are compared with values of the same type.
+func compareValues(a, b interface{}) (int, error) {
+	if bytes, ok := a.([]byte); ok {
+		a = string(bytes)
+	}
+	if bytes, ok := b.([]byte); ok {
+		b = string(bytes)
+	}
+	if _, ok := a.(string); ok {
+		if _, ok = b.(string); !ok {
+			order, err := compareValues(b, a)
+			return -order, err
+		}
+	}
+
+	switch x := a.(type) {
+	case int64:
+		if y, ok := b.(int64); ok {
+			return compareIntegers(x, y), nil
+		}
+		return compareNumbers(float64(x), b)
+	case float64:
+		return compareNumbers(x, b)
+	case string:
+		return strings.Compare(x, b.(string)), nil
+	case bool:
+		if y, ok := b.(bool); ok {
+			return compareIntegers(boolRank(x), boolRank(y)), nil
+		}
+	case time.Time:
+		switch y := b.(type) {
+		case time.Time:
+			return compareTimes(x, y), nil
+		case string:
+			if t, ok := parseTime(y, x.Location()); ok {
+				return compareTimes(x, t), nil
+			}
+			return 0, fmt.Errorf("cannot compare time with \"%s\"", y)
+		}
+	}
+	return 0, fmt.Errorf("cannot compare %T with %T", a, b)
+}
+
+// compareNumbers compares the number x with b, which is a number or a string holding one.
+func compareNumbers(x float64, b interface{}) (int, error) {
+	switch y := b.(type) {
+	case int64:
+		return compareFloats(x, float64(y)), nil
+	case float64:
+		return compareFloats(x, y), nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(y), 64)
+		if err != nil {
+			return 0, fmt.Errorf("cannot compare number with \"%s\"", y)
+		}
+		return compareFloats(x, f), nil
+	}
+	return 0, fmt.Errorf("cannot compare float64 with %T", b)
+}
+
+func compareIntegers(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func compareFloats(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func compareTimes(x, y time.Time) int {
+	switch {
+	case x.Before(y):
+		return -1
+	case x.After(y):
+		return 1
+	}
+	return 0
+}
+
+func boolRank(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// parseTime parses the string as per one of the timeLayouts, in the location given.
+func parseTime(s string, loc *time.Location) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// isTemporal returns true for the types of fields that hold dates.
+func isTemporal(fieldType godbf.DbaseDataType) bool {
+	return fieldType == godbf.Date || fieldType == godbf.DateTime || fieldType == godbf.Timestamp
+}
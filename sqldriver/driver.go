@@ -0,0 +1,161 @@
+// Package sqldriver provides a database/sql driver for dBase tables, registered as "dbf".
+//
+// The data source name is the path of a directory, whose .dbf files are its tables, or of a single .dbf file. Tables
+// are named after their files without the extension, and are matched regardless of case, as are their columns. The
+// encoding of the tables is detected from them unless it is named by an encoding parameter, as in
+// "data/customers?encoding=ibm866":
+//
+//	db, err := sql.Open("dbf", "data")
+//	rows, err := db.Query("SELECT NAME, BORN FROM customers WHERE AMOUNT > ? ORDER BY NAME LIMIT 10", 100)
+//
+// A subset of SQL is supported:
+//
+//	SELECT * | column, ... FROM table [WHERE condition] [ORDER BY column [ASC | DESC], ...] [LIMIT n [OFFSET m]]
+//	INSERT INTO table [(column, ...)] VALUES (value, ...), ...
+//	UPDATE table SET column = value, ... [WHERE condition]
+//	DELETE FROM table [WHERE condition]
+//
+// Conditions compare columns and values with =, <>, !=, <, <=, > and >=, check for NULL with IS [NOT] NULL, and are
+// combined with AND, OR, NOT and parentheses. Values are strings in single quotes, numbers, TRUE, FALSE, NULL and ?
+// placeholders. Strings are compared with dates as per the layouts 2006-01-02 and 20060102.
+//
+// Values are returned as per godbf.DbfTable.TypedFieldValue(): Character and Memo fields as string, Numeric and Float
+// fields as int64 if they have no decimal places and as float64 otherwise, Logical fields as bool, and Date fields as
+// time.Time. Records marked as deleted are skipped. Values are stored as per godbf.DbfTable.SetTypedFieldValue(),
+// INSERT adds records with AddNewRecord(), and DELETE marks records as deleted, so that they can be recalled until the
+// table is packed.
+//
+// Each statement reads the table from its file, and statements that change it write it back before they return, so
+// that a statement that fails leaves the file unchanged. Transactions are not supported. Index files are not
+// maintained.
+package sqldriver
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/NovikovRoman/godbf"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// DriverName is the name under which the driver is registered with database/sql.
+const DriverName = "dbf"
+
+const tableFileExtension = ".dbf"
+
+// fileLock serialises access to the table files, so that statements that change a table do not overlap with others.
+var fileLock sync.RWMutex
+
+func init() {
+	sql.Register(DriverName, &Driver{})
+}
+
+// Driver is the database/sql driver for dBase tables.
+type Driver struct{}
+
+// Open returns a connection to the directory or file named by the data source name, as described by the package
+// documentation.
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	path, query := dsn, ""
+	if i := strings.LastIndex(dsn, "?"); i >= 0 {
+		path, query = dsn[:i], dsn[i+1:]
+	}
+
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters \"%s\": %v", query, err)
+	}
+
+	c := &conn{path: path, enc: godbf.Auto(nil)}
+	for name, values := range params {
+		switch name {
+		case "encoding":
+			if c.enc, err = htmlindex.Get(values[0]); err != nil {
+				return nil, fmt.Errorf("unknown encoding \"%s\"", values[0])
+			}
+		default:
+			return nil, fmt.Errorf("unknown parameter \"%s\"", name)
+		}
+	}
+
+	var info os.FileInfo
+	if info, err = os.Stat(path); err != nil {
+		return nil, err
+	}
+	c.isDir = info.IsDir()
+	return c, nil
+}
+
+// conn is a connection to a directory of tables, or to a single table.
+type conn struct {
+	path  string
+	isDir bool
+	enc   encoding.Encoding
+}
+
+// Prepare parses the query, which is executed by the statement returned.
+func (c *conn) Prepare(query string) (driver.Stmt, error) {
+	parsed, err := parse(query)
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{conn: c, statement: parsed}, nil
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("transactions are not supported")
+}
+
+// tableFileName returns the name of the file of the table with the given name.
+func (c *conn) tableFileName(tableName string) (string, error) {
+	if !c.isDir {
+		base := filepath.Base(c.path)
+		if strings.EqualFold(strings.TrimSuffix(base, filepath.Ext(base)), tableName) {
+			return c.path, nil
+		}
+		return "", fmt.Errorf("table \"%s\" does not exist", tableName)
+	}
+
+	entries, err := os.ReadDir(c.path)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		ext := filepath.Ext(name)
+		if !entry.IsDir() && strings.EqualFold(ext, tableFileExtension) &&
+			strings.EqualFold(strings.TrimSuffix(name, ext), tableName) {
+			return filepath.Join(c.path, name), nil
+		}
+	}
+	return "", fmt.Errorf("table \"%s\" does not exist", tableName)
+}
+
+// loadTable reads the table with the given name from its file.
+func (c *conn) loadTable(tableName string) (table *godbf.DbfTable, fileName string, err error) {
+	if fileName, err = c.tableFileName(tableName); err != nil {
+		return
+	}
+	table, err = godbf.NewFromFile(fileName, c.enc)
+	return
+}
+
+// saveTable writes the table back to its file, keeping the file's permissions.
+func saveTable(table *godbf.DbfTable, fileName string) error {
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return err
+	}
+	return table.Save(fileName, info.Mode().Perm())
+}
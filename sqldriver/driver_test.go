@@ -0,0 +1,220 @@
+package sqldriver
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NovikovRoman/godbf"
+	"github.com/stretchr/testify/require"
+)
+
+type customerRow struct {
+	name    string
+	visits  sql.NullInt64
+	balance sql.NullFloat64
+	active  sql.NullBool
+	born    sql.NullTime
+}
+
+// createCustomersDir creates a directory holding a customers table, returning the directory.
+func createCustomersDir(t *testing.T) string {
+	table := godbf.New(nil)
+	require.Nil(t, table.AddTextField("NAME", 20))
+	require.Nil(t, table.AddNumberField("VISITS", 4, 0))
+	require.Nil(t, table.AddNumberField("BALANCE", 10, 2))
+	require.Nil(t, table.AddBooleanField("ACTIVE"))
+	require.Nil(t, table.AddDateField("BORN"))
+
+	for _, values := range [][]string{
+		{"Ada", "12", "1234.56", "T", "18151210"},
+		{"Charles", "3", "-10.5", "F", "17911226"},
+		{"Grace", "", "99.99", "T", "19061209"},
+		{"Alan", "7", "", "?", ""},
+	} {
+		row, err := table.AddNewRecord()
+		require.Nil(t, err)
+		for i, value := range values {
+			require.Nil(t, table.SetFieldValue(row, i, value))
+		}
+	}
+
+	dir := t.TempDir()
+	require.Nil(t, table.Save(filepath.Join(dir, "CUSTOMERS.DBF"), 0644))
+	return dir
+}
+
+func queryCustomers(t *testing.T, db *sql.DB, query string, args ...interface{}) (customers []customerRow) {
+	rows, err := db.Query(query, args...)
+	require.Nil(t, err)
+	defer rows.Close()
+
+	for rows.Next() {
+		var c customerRow
+		require.Nil(t, rows.Scan(&c.name, &c.visits, &c.balance, &c.active, &c.born))
+		customers = append(customers, c)
+	}
+	require.Nil(t, rows.Err())
+	return
+}
+
+func names(customers []customerRow) (names []string) {
+	for _, c := range customers {
+		names = append(names, c.name)
+	}
+	return
+}
+
+func TestDriver_Select(t *testing.T) {
+	db, err := sql.Open(DriverName, createCustomersDir(t))
+	require.Nil(t, err)
+	defer db.Close()
+
+	customers := queryCustomers(t, db, "SELECT * FROM customers")
+	require.Len(t, customers, 4)
+	require.Equal(t, customers[0], customerRow{
+		name:    "Ada",
+		visits:  sql.NullInt64{Int64: 12, Valid: true},
+		balance: sql.NullFloat64{Float64: 1234.56, Valid: true},
+		active:  sql.NullBool{Bool: true, Valid: true},
+		born:    sql.NullTime{Time: time.Date(1815, time.December, 10, 0, 0, 0, 0, time.Local), Valid: true},
+	})
+	require.Equal(t, customers[3], customerRow{name: "Alan", visits: sql.NullInt64{Int64: 7, Valid: true}})
+
+	for query, expected := range map[string][]string{
+		"SELECT * FROM customers WHERE balance > 0 ORDER BY name":                              {"Ada", "Grace"},
+		"SELECT * FROM customers WHERE active = TRUE AND NOT visits IS NULL":                   {"Ada"},
+		"SELECT * FROM customers WHERE visits IS NULL OR name = 'Charles' ORDER BY name DESC":  {"Grace", "Charles"},
+		"SELECT * FROM customers WHERE NOT visits < 5":                                         {"Ada", "Alan"},
+		"SELECT * FROM customers WHERE born < '1900-01-01' ORDER BY born":                      {"Charles", "Ada"},
+		"SELECT * FROM customers WHERE born = '18151210'":                                      {"Ada"},
+		"SELECT * FROM customers ORDER BY visits DESC, name LIMIT 2":                           {"Ada", "Alan"},
+		"SELECT * FROM customers ORDER BY balance LIMIT 2 OFFSET 1":                            {"Charles", "Grace"},
+		"SELECT * FROM customers WHERE (visits >= 7 OR active <> TRUE) AND balance <> 1234.56": {"Charles"},
+		"SELECT * FROM customers LIMIT 0":                                                      nil,
+	} {
+		require.Equal(t, names(queryCustomers(t, db, query)), expected, query)
+	}
+
+	customers = queryCustomers(t, db, "SELECT * FROM customers WHERE name = ? OR visits = ?", "Grace", 3)
+	require.Equal(t, names(customers), []string{"Charles", "Grace"})
+
+	var name string
+	var balance float64
+	require.Nil(t, db.QueryRow("SELECT \"Name\", BALANCE FROM Customers WHERE born > ?",
+		time.Date(1900, time.January, 1, 0, 0, 0, 0, time.Local)).Scan(&name, &balance))
+	require.Equal(t, name, "Grace")
+	require.Equal(t, balance, 99.99)
+
+	rows, err := db.Query("SELECT visits, name FROM customers LIMIT 1")
+	require.Nil(t, err)
+	columns, err := rows.Columns()
+	require.Nil(t, err)
+	require.Equal(t, columns, []string{"VISITS", "NAME"})
+	require.Nil(t, rows.Close())
+}
+
+func TestDriver_InsertUpdateDelete(t *testing.T) {
+	dir := createCustomersDir(t)
+	db, err := sql.Open(DriverName, dir)
+	require.Nil(t, err)
+	defer db.Close()
+
+	result, err := db.Exec("INSERT INTO customers (NAME, VISITS, BALANCE, ACTIVE, BORN) VALUES (?, ?, ?, ?, ?), "+
+		"('Edsger', NULL, -0.5, FALSE, '1930-05-11')",
+		"Barbara", 1, 12.345, true, time.Date(1939, time.November, 7, 0, 0, 0, 0, time.UTC))
+	require.Nil(t, err)
+	lastRow, err := result.LastInsertId()
+	require.Nil(t, err)
+	require.EqualValues(t, lastRow, 5)
+	affected, err := result.RowsAffected()
+	require.Nil(t, err)
+	require.EqualValues(t, affected, 2)
+
+	result, err = db.Exec("UPDATE customers SET visits = visits, balance = 0 WHERE balance < 0")
+	require.EqualError(t, err, "expected value at position 30, found \"visits\"")
+	result, err = db.Exec("UPDATE customers SET visits = 100, balance = 0 WHERE balance < 0")
+	require.Nil(t, err)
+	affected, err = result.RowsAffected()
+	require.Nil(t, err)
+	require.EqualValues(t, affected, 2)
+
+	result, err = db.Exec("DELETE FROM customers WHERE active = FALSE")
+	require.Nil(t, err)
+	affected, err = result.RowsAffected()
+	require.Nil(t, err)
+	require.EqualValues(t, affected, 2)
+
+	customers := queryCustomers(t, db, "SELECT * FROM customers ORDER BY name")
+	require.Equal(t, names(customers), []string{"Ada", "Alan", "Barbara", "Grace"})
+	require.Equal(t, customers[2].balance.Float64, 12.35)
+	require.Equal(t, customers[2].born.Time, time.Date(1939, time.November, 7, 0, 0, 0, 0, time.Local))
+
+	// changes are saved to the file, with deleted records marked rather than removed
+	table, err := godbf.NewFromFile(filepath.Join(dir, "CUSTOMERS.DBF"), nil)
+	require.Nil(t, err)
+	require.Equal(t, table.NumberOfRecords(), 6)
+	require.True(t, table.RowIsDeleted(1))
+	require.True(t, table.RowIsDeleted(5))
+	require.Equal(t, table.GetRowAsSlice(5), []string{"Edsger", "100", "0.00", "F", "19300511"})
+
+	// a statement that fails leaves the file unchanged
+	_, err = db.Exec("INSERT INTO customers (NAME, VISITS) VALUES ('Ken', 1), ('Dennis', 10000)")
+	require.EqualError(t, err, "row 7, field \"VISITS\": value 10000 does not fit field \"VISITS\" of length 4")
+	table, err = godbf.NewFromFile(filepath.Join(dir, "CUSTOMERS.DBF"), nil)
+	require.Nil(t, err)
+	require.Equal(t, table.NumberOfRecords(), 6)
+}
+
+func TestDriver_SingleFile(t *testing.T) {
+	fileName := filepath.Join(createCustomersDir(t), "CUSTOMERS.DBF")
+	db, err := sql.Open(DriverName, fileName+"?encoding=windows-1252")
+	require.Nil(t, err)
+	defer db.Close()
+
+	var count int
+	rows, err := db.Query("SELECT name FROM customers")
+	require.Nil(t, err)
+	for rows.Next() {
+		count++
+	}
+	require.Equal(t, count, 4)
+
+	_, err = db.Query("SELECT name FROM orders")
+	require.EqualError(t, err, "table \"orders\" does not exist")
+}
+
+func TestDriver_Errors(t *testing.T) {
+	dir := createCustomersDir(t)
+	db, err := sql.Open(DriverName, dir)
+	require.Nil(t, err)
+	defer db.Close()
+
+	for query, expected := range map[string]string{
+		"SELECT city FROM customers":                     "column \"city\" does not exist",
+		"SELECT * FROM orders":                           "table \"orders\" does not exist",
+		"SELECT * FROM customers WHERE name > 1":         "cannot compare number with \"Ada\"",
+		"SELECT * FROM customers WHERE born = 'someday'": "cannot compare time with \"someday\"",
+		"SELECT * FROM customers ORDER BY city":          "column \"city\" does not exist",
+		"DELETE FROM customers":                          "only SELECT statements return rows, use Exec() instead",
+	} {
+		_, err = db.Query(query)
+		require.EqualError(t, err, expected, query)
+	}
+
+	_, err = db.Exec("SELECT * FROM customers")
+	require.EqualError(t, err, "SELECT statements return rows, use Query() instead")
+	_, err = db.Exec("INSERT INTO customers VALUES ('Ken')")
+	require.EqualError(t, err, "1 values are given for 5 columns")
+	_, err = db.Begin()
+	require.EqualError(t, err, "transactions are not supported")
+
+	db, err = sql.Open(DriverName, filepath.Join(dir, "missing"))
+	require.Nil(t, err)
+	require.True(t, os.IsNotExist(db.Ping()))
+	db, err = sql.Open(DriverName, dir+"?encoding=nope")
+	require.Nil(t, err)
+	require.EqualError(t, db.Ping(), "unknown encoding \"nope\"")
+}
@@ -0,0 +1,564 @@
+package sqldriver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// statementKind is the kind of an SQL statement.
+type statementKind int
+
+const (
+	selectStatement statementKind = iota
+	insertStatement
+	updateStatement
+	deleteStatement
+)
+
+// statement is a parsed SQL statement. Column names are resolved against the fields of the table when the statement
+// is executed, as the table may change between executions.
+type statement struct {
+	kind        statementKind
+	table       string
+	columns     []string     // the projected columns of SELECT, empty for *, or the columns of INSERT
+	values      [][]operand  // the rows of values of INSERT
+	assignments []assignment // the SET clause of UPDATE
+	where       condition    // nil if there is no WHERE clause
+	orderBy     []orderTerm
+	limit       int // -1 if there is no LIMIT clause
+	offset      int
+	numInput    int // the number of ? placeholders
+}
+
+type assignment struct {
+	column string
+	value  operand
+}
+
+type orderTerm struct {
+	column     string
+	descending bool
+}
+
+// operand is a column, a literal value or a placeholder, as compared in conditions and assigned to fields.
+type operand struct {
+	column      string // the name of the column, empty for literals and placeholders
+	value       interface{}
+	placeholder int // the 1-based position of the placeholder, 0 for columns and literals
+}
+
+// condition is an expression of a WHERE clause.
+type condition interface{}
+
+type comparison struct {
+	operator    string // one of = <> < <= > >=
+	left, right operand
+}
+
+type nullCheck struct {
+	operand operand
+	not     bool // IS NOT NULL
+}
+
+type logical struct {
+	operator    string // AND or OR
+	left, right condition
+}
+
+type negation struct {
+	condition condition
+}
+
+// tokenKind is the kind of a token of an SQL statement.
+type tokenKind int
+
+const (
+	endToken tokenKind = iota
+	identifierToken
+	quotedIdentifierToken
+	numberToken
+	stringToken
+	symbolToken
+)
+
+// symbols are the operators and punctuation of SQL statements.
+var symbols = map[string]bool{
+	"*": true, ",": true, "(": true, ")": true, ";": true, "?": true, "-": true, "+": true,
+	"=": true, "<>": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true,
+}
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// tokenize splits an SQL statement into tokens, ending with an endToken.
+func tokenize(query string) (tokens []token, err error) {
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{identifierToken, string(runes[start:i]), start})
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == 'e' || runes[i] == 'E' ||
+				((runes[i] == '-' || runes[i] == '+') && (runes[i-1] == 'e' || runes[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, token{numberToken, string(runes[start:i]), start})
+		case r == '\'' || r == '"' || r == '`':
+			var text strings.Builder
+			for i++; ; i++ {
+				if i >= len(runes) {
+					return nil, fmt.Errorf("unterminated quote at position %d", start)
+				}
+				if runes[i] == r {
+					if i+1 < len(runes) && runes[i+1] == r {
+						i++
+					} else {
+						break
+					}
+				}
+				text.WriteRune(runes[i])
+			}
+			i++
+			kind := quotedIdentifierToken
+			if r == '\'' {
+				kind = stringToken
+			}
+			tokens = append(tokens, token{kind, text.String(), start})
+		default:
+			i++
+			if i < len(runes) {
+				if two := string(runes[start : i+1]); two == "<=" || two == ">=" || two == "<>" || two == "!=" {
+					i++
+				}
+			}
+			symbol := string(runes[start:i])
+			if !symbols[symbol] {
+				return nil, fmt.Errorf("unexpected character '%s' at position %d", symbol, start)
+			}
+			tokens = append(tokens, token{symbolToken, symbol, start})
+		}
+	}
+	return append(tokens, token{kind: endToken, pos: len(runes)}), nil
+}
+
+// parser parses the tokens of an SQL statement.
+type parser struct {
+	tokens   []token
+	pos      int
+	numInput int
+}
+
+// parse parses a SELECT, INSERT, UPDATE or DELETE statement.
+func parse(query string) (stmt *statement, err error) {
+	p := &parser{}
+	if p.tokens, err = tokenize(query); err != nil {
+		return
+	}
+
+	switch {
+	case p.acceptKeyword("SELECT"):
+		stmt, err = p.parseSelect()
+	case p.acceptKeyword("INSERT"):
+		stmt, err = p.parseInsert()
+	case p.acceptKeyword("UPDATE"):
+		stmt, err = p.parseUpdate()
+	case p.acceptKeyword("DELETE"):
+		stmt, err = p.parseDelete()
+	default:
+		return nil, p.unexpected("SELECT, INSERT, UPDATE or DELETE")
+	}
+	if err != nil {
+		return
+	}
+
+	p.acceptSymbol(";")
+	if p.peek().kind != endToken {
+		return nil, p.unexpected("end of statement")
+	}
+	stmt.numInput = p.numInput
+	return
+}
+
+// parseSelect parses SELECT columns FROM table [WHERE condition] [ORDER BY columns] [LIMIT n [OFFSET m]].
+func (p *parser) parseSelect() (stmt *statement, err error) {
+	stmt = &statement{kind: selectStatement, limit: -1}
+	if !p.acceptSymbol("*") {
+		if stmt.columns, err = p.parseIdentifierList(); err != nil {
+			return
+		}
+	}
+	if err = p.expectKeyword("FROM"); err != nil {
+		return
+	}
+	if stmt.table, err = p.parseIdentifier(); err != nil {
+		return
+	}
+	if stmt.where, err = p.parseWhere(); err != nil {
+		return
+	}
+
+	if p.acceptKeyword("ORDER") {
+		if err = p.expectKeyword("BY"); err != nil {
+			return
+		}
+		for {
+			var term orderTerm
+			if term.column, err = p.parseIdentifier(); err != nil {
+				return
+			}
+			if p.acceptKeyword("DESC") {
+				term.descending = true
+			} else {
+				p.acceptKeyword("ASC")
+			}
+			stmt.orderBy = append(stmt.orderBy, term)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+	}
+
+	if p.acceptKeyword("LIMIT") {
+		if stmt.limit, err = p.parseCount(); err != nil {
+			return
+		}
+		if p.acceptKeyword("OFFSET") {
+			stmt.offset, err = p.parseCount()
+		}
+	}
+	return
+}
+
+// parseInsert parses INSERT INTO table [(columns)] VALUES (values) [, (values)].
+func (p *parser) parseInsert() (stmt *statement, err error) {
+	stmt = &statement{kind: insertStatement}
+	if err = p.expectKeyword("INTO"); err != nil {
+		return
+	}
+	if stmt.table, err = p.parseIdentifier(); err != nil {
+		return
+	}
+	if p.acceptSymbol("(") {
+		if stmt.columns, err = p.parseIdentifierList(); err != nil {
+			return
+		}
+		if err = p.expectSymbol(")"); err != nil {
+			return
+		}
+	}
+	if err = p.expectKeyword("VALUES"); err != nil {
+		return
+	}
+
+	for {
+		if err = p.expectSymbol("("); err != nil {
+			return
+		}
+		var values []operand
+		for {
+			var value operand
+			if value, err = p.parseValue(); err != nil {
+				return
+			}
+			values = append(values, value)
+			if !p.acceptSymbol(",") {
+				break
+			}
+		}
+		if err = p.expectSymbol(")"); err != nil {
+			return
+		}
+		if len(stmt.columns) > 0 && len(values) != len(stmt.columns) {
+			return nil, fmt.Errorf("%d values are given for %d columns", len(values), len(stmt.columns))
+		}
+		stmt.values = append(stmt.values, values)
+		if !p.acceptSymbol(",") {
+			return
+		}
+	}
+}
+
+// parseUpdate parses UPDATE table SET column = value [, column = value] [WHERE condition].
+func (p *parser) parseUpdate() (stmt *statement, err error) {
+	stmt = &statement{kind: updateStatement}
+	if stmt.table, err = p.parseIdentifier(); err != nil {
+		return
+	}
+	if err = p.expectKeyword("SET"); err != nil {
+		return
+	}
+	for {
+		var a assignment
+		if a.column, err = p.parseIdentifier(); err != nil {
+			return
+		}
+		if err = p.expectSymbol("="); err != nil {
+			return
+		}
+		if a.value, err = p.parseValue(); err != nil {
+			return
+		}
+		stmt.assignments = append(stmt.assignments, a)
+		if !p.acceptSymbol(",") {
+			break
+		}
+	}
+	stmt.where, err = p.parseWhere()
+	return
+}
+
+// parseDelete parses DELETE FROM table [WHERE condition].
+func (p *parser) parseDelete() (stmt *statement, err error) {
+	stmt = &statement{kind: deleteStatement}
+	if err = p.expectKeyword("FROM"); err != nil {
+		return
+	}
+	if stmt.table, err = p.parseIdentifier(); err != nil {
+		return
+	}
+	stmt.where, err = p.parseWhere()
+	return
+}
+
+func (p *parser) parseWhere() (condition, error) {
+	if !p.acceptKeyword("WHERE") {
+		return nil, nil
+	}
+	return p.parseOr()
+}
+
+func (p *parser) parseOr() (c condition, err error) {
+	if c, err = p.parseAnd(); err != nil {
+		return
+	}
+	for p.acceptKeyword("OR") {
+		var right condition
+		if right, err = p.parseAnd(); err != nil {
+			return
+		}
+		c = logical{operator: "OR", left: c, right: right}
+	}
+	return
+}
+
+func (p *parser) parseAnd() (c condition, err error) {
+	if c, err = p.parseNot(); err != nil {
+		return
+	}
+	for p.acceptKeyword("AND") {
+		var right condition
+		if right, err = p.parseNot(); err != nil {
+			return
+		}
+		c = logical{operator: "AND", left: c, right: right}
+	}
+	return
+}
+
+func (p *parser) parseNot() (c condition, err error) {
+	if p.acceptKeyword("NOT") {
+		if c, err = p.parseNot(); err != nil {
+			return
+		}
+		return negation{condition: c}, nil
+	}
+	return p.parsePredicate()
+}
+
+// parsePredicate parses a parenthesised condition, a comparison, or a check for NULL.
+func (p *parser) parsePredicate() (c condition, err error) {
+	if p.acceptSymbol("(") {
+		if c, err = p.parseOr(); err != nil {
+			return
+		}
+		return c, p.expectSymbol(")")
+	}
+
+	var left operand
+	if left, err = p.parseOperand(); err != nil {
+		return
+	}
+	if p.acceptKeyword("IS") {
+		check := nullCheck{operand: left, not: p.acceptKeyword("NOT")}
+		return check, p.expectKeyword("NULL")
+	}
+
+	t := p.peek()
+	switch t.text {
+	case "=", "<>", "!=", "<", "<=", ">", ">=":
+		if t.kind != symbolToken {
+			break
+		}
+		p.pos++
+		operator := t.text
+		if operator == "!=" {
+			operator = "<>"
+		}
+		var right operand
+		if right, err = p.parseOperand(); err != nil {
+			return
+		}
+		return comparison{operator: operator, left: left, right: right}, nil
+	}
+	return nil, p.unexpected("comparison operator")
+}
+
+// parseOperand parses a column name or a value.
+func (p *parser) parseOperand() (operand, error) {
+	if t := p.peek(); t.kind == quotedIdentifierToken || (t.kind == identifierToken && !isValueKeyword(t.text)) {
+		p.pos++
+		return operand{column: t.text}, nil
+	}
+	return p.parseValue()
+}
+
+// parseValue parses a literal value or a placeholder.
+func (p *parser) parseValue() (operand, error) {
+	t := p.peek()
+	switch t.kind {
+	case stringToken:
+		p.pos++
+		return operand{value: t.text}, nil
+	case numberToken:
+		p.pos++
+		return parseNumber(t.text, false)
+	case identifierToken:
+		switch strings.ToUpper(t.text) {
+		case "NULL":
+			p.pos++
+			return operand{}, nil
+		case "TRUE":
+			p.pos++
+			return operand{value: true}, nil
+		case "FALSE":
+			p.pos++
+			return operand{value: false}, nil
+		}
+	case symbolToken:
+		switch t.text {
+		case "?":
+			p.pos++
+			p.numInput++
+			return operand{placeholder: p.numInput}, nil
+		case "-", "+":
+			if next := p.tokens[p.pos+1]; next.kind == numberToken {
+				p.pos += 2
+				return parseNumber(next.text, t.text == "-")
+			}
+		}
+	}
+	return operand{}, p.unexpected("value")
+}
+
+// parseNumber parses an integer as an int64 and any other number as a float64.
+func parseNumber(text string, negative bool) (operand, error) {
+	if negative {
+		text = "-" + text
+	}
+	if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return operand{value: i}, nil
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return operand{}, fmt.Errorf("invalid number \"%s\"", text)
+	}
+	return operand{value: f}, nil
+}
+
+// parseCount parses the non-negative integer of a LIMIT or OFFSET clause.
+func (p *parser) parseCount() (int, error) {
+	t := p.peek()
+	if t.kind == numberToken {
+		if n, err := strconv.Atoi(t.text); err == nil {
+			p.pos++
+			return n, nil
+		}
+	}
+	return 0, p.unexpected("non-negative integer")
+}
+
+func (p *parser) parseIdentifierList() (identifiers []string, err error) {
+	for {
+		var identifier string
+		if identifier, err = p.parseIdentifier(); err != nil {
+			return
+		}
+		identifiers = append(identifiers, identifier)
+		if !p.acceptSymbol(",") {
+			return
+		}
+	}
+}
+
+func (p *parser) parseIdentifier() (string, error) {
+	if t := p.peek(); t.kind == identifierToken || t.kind == quotedIdentifierToken {
+		p.pos++
+		return t.text, nil
+	}
+	return "", p.unexpected("name")
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) acceptKeyword(keyword string) bool {
+	if t := p.peek(); t.kind == identifierToken && strings.EqualFold(t.text, keyword) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectKeyword(keyword string) error {
+	if !p.acceptKeyword(keyword) {
+		return p.unexpected(keyword)
+	}
+	return nil
+}
+
+func (p *parser) acceptSymbol(symbol string) bool {
+	if t := p.peek(); t.kind == symbolToken && t.text == symbol {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expectSymbol(symbol string) error {
+	if !p.acceptSymbol(symbol) {
+		return p.unexpected("\"" + symbol + "\"")
+	}
+	return nil
+}
+
+// unexpected returns an error describing the current token, and what was expected in its place.
+func (p *parser) unexpected(expected string) error {
+	t := p.peek()
+	if t.kind == endToken {
+		return fmt.Errorf("expected %s at end of statement", expected)
+	}
+	return fmt.Errorf("expected %s at position %d, found \"%s\"", expected, t.pos, t.text)
+}
+
+// isValueKeyword returns true if the identifier is a keyword that denotes a value rather than a column.
+func isValueKeyword(identifier string) bool {
+	switch strings.ToUpper(identifier) {
+	case "NULL", "TRUE", "FALSE":
+		return true
+	}
+	return false
+}
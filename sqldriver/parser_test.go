@@ -0,0 +1,71 @@
+package sqldriver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Select(t *testing.T) {
+	stmt, err := parse("select NAME, \"last name\" from `people` where AGE >= -1.5e2 and not (CITY = 'O''Hare' or CITY is not null) " +
+		"order by NAME desc, AGE limit 10 offset ?;")
+	require.EqualError(t, err, "expected non-negative integer at position 146, found \"?\"")
+
+	stmt, err = parse("select NAME, \"last name\" from `people` where AGE >= -1.5e2 and not (CITY = 'O''Hare' or CITY is not null) " +
+		"order by NAME desc, AGE limit 10 offset 5;")
+	require.Nil(t, err)
+	require.Equal(t, stmt, &statement{
+		kind:    selectStatement,
+		table:   "people",
+		columns: []string{"NAME", "last name"},
+		where: logical{
+			operator: "AND",
+			left:     comparison{operator: ">=", left: operand{column: "AGE"}, right: operand{value: -150.0}},
+			right: negation{condition: logical{
+				operator: "OR",
+				left:     comparison{operator: "=", left: operand{column: "CITY"}, right: operand{value: "O'Hare"}},
+				right:    nullCheck{operand: operand{column: "CITY"}, not: true},
+			}},
+		},
+		orderBy: []orderTerm{{column: "NAME", descending: true}, {column: "AGE"}},
+		limit:   10,
+		offset:  5,
+	})
+}
+
+func TestParse_Changes(t *testing.T) {
+	stmt, err := parse("INSERT INTO t (A, B) VALUES (?, NULL), (TRUE, 42)")
+	require.Nil(t, err)
+	require.Equal(t, stmt.values, [][]operand{{{placeholder: 1}, {}}, {{value: true}, {value: int64(42)}}})
+	require.Equal(t, stmt.numInput, 1)
+
+	stmt, err = parse("UPDATE t SET A = ?, B = 'x' WHERE C != ?")
+	require.Nil(t, err)
+	require.Equal(t, stmt.assignments, []assignment{{"A", operand{placeholder: 1}}, {"B", operand{value: "x"}}})
+	require.Equal(t, stmt.where, comparison{operator: "<>", left: operand{column: "C"}, right: operand{placeholder: 2}})
+	require.Equal(t, stmt.numInput, 2)
+
+	stmt, err = parse("DELETE FROM t")
+	require.Nil(t, err)
+	require.Equal(t, stmt, &statement{kind: deleteStatement, table: "t"})
+}
+
+func TestParse_Errors(t *testing.T) {
+	for query, expected := range map[string]string{
+		"DROP TABLE t":                        "expected SELECT, INSERT, UPDATE or DELETE at position 0, found \"DROP\"",
+		"SELECT FROM t":                       "expected FROM at position 12, found \"t\"",
+		"SELECT * FROM t WHERE":               "expected value at end of statement",
+		"SELECT * FROM t WHERE A":             "expected comparison operator at end of statement",
+		"SELECT * FROM t WHERE A LIKE 'x'":    "expected comparison operator at position 24, found \"LIKE\"",
+		"SELECT * FROM t WHERE A = 'x":        "unterminated quote at position 26",
+		"SELECT * FROM t WHERE A = #":         "unexpected character '#' at position 26",
+		"SELECT * FROM t extra":               "expected end of statement at position 16, found \"extra\"",
+		"INSERT INTO t (A, B) VALUES (1)":     "1 values are given for 2 columns",
+		"INSERT INTO t VALUES (1":             "expected \")\" at end of statement",
+		"UPDATE t SET A 1":                    "expected \"=\" at position 15, found \"1\"",
+		"SELECT * FROM t WHERE A = 1e999999x": "invalid number \"1e999999\"",
+	} {
+		_, err := parse(query)
+		require.EqualError(t, err, expected, query)
+	}
+}
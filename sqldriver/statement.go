@@ -0,0 +1,440 @@
+package sqldriver
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/NovikovRoman/godbf"
+)
+
+// stmt is a prepared statement.
+type stmt struct {
+	conn      *conn
+	statement *statement
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return s.statement.numInput
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.conn.exec(s.statement, args)
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	values, err := ordinalValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return s.Exec(values)
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.conn.query(s.statement, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	values, err := ordinalValues(args)
+	if err != nil {
+		return nil, err
+	}
+	return s.Query(values)
+}
+
+// ordinalValues returns the values of the arguments, which must not be named.
+func ordinalValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, fmt.Errorf("named argument \"%s\" is not supported", arg.Name)
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// query executes a SELECT statement, returning the values of the matching records.
+func (c *conn) query(s *statement, args []driver.Value) (driver.Rows, error) {
+	if s.kind != selectStatement {
+		return nil, fmt.Errorf("only SELECT statements return rows, use Exec() instead")
+	}
+
+	fileLock.RLock()
+	table, _, err := c.loadTable(s.table)
+	fileLock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	e := newEvaluator(table, args)
+	var columns []int
+	if len(s.columns) == 0 {
+		for i := range table.Fields() {
+			columns = append(columns, i)
+		}
+	} else {
+		for _, name := range s.columns {
+			var i int
+			if i, err = e.columnIndex(name); err != nil {
+				return nil, err
+			}
+			columns = append(columns, i)
+		}
+	}
+
+	var matches []int
+	if matches, err = e.matchingRows(s.where); err != nil {
+		return nil, err
+	}
+	if matches, err = e.sortRows(matches, s.orderBy); err != nil {
+		return nil, err
+	}
+	if s.offset >= len(matches) {
+		matches = nil
+	} else {
+		matches = matches[s.offset:]
+	}
+	if s.limit >= 0 && s.limit < len(matches) {
+		matches = matches[:s.limit]
+	}
+
+	r := &rows{values: make([][]driver.Value, len(matches))}
+	fields := table.Fields()
+	for _, i := range columns {
+		r.columns = append(r.columns, fields[i].Name())
+	}
+	for n, row := range matches {
+		r.values[n] = make([]driver.Value, len(columns))
+		for j, i := range columns {
+			if r.values[n][j], err = table.TypedFieldValue(row, i); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return r, nil
+}
+
+// exec executes an INSERT, UPDATE or DELETE statement, writing the changed table back to its file.
+func (c *conn) exec(s *statement, args []driver.Value) (result driver.Result, err error) {
+	if s.kind == selectStatement {
+		return nil, fmt.Errorf("SELECT statements return rows, use Query() instead")
+	}
+
+	fileLock.Lock()
+	defer fileLock.Unlock()
+
+	table, fileName, err := c.loadTable(s.table)
+	if err != nil {
+		return nil, err
+	}
+
+	e := newEvaluator(table, args)
+	switch s.kind {
+	case insertStatement:
+		result, err = e.insert(s)
+	case updateStatement:
+		result, err = e.update(s)
+	case deleteStatement:
+		result, err = e.delete(s)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, saveTable(table, fileName)
+}
+
+// insert adds a record for each row of values of the INSERT statement.
+func (e *evaluator) insert(s *statement) (driver.Result, error) {
+	columns := make([]int, len(s.columns))
+	for i, name := range s.columns {
+		var err error
+		if columns[i], err = e.columnIndex(name); err != nil {
+			return nil, err
+		}
+	}
+	if len(columns) == 0 {
+		for i := range e.table.Fields() {
+			columns = append(columns, i)
+		}
+	}
+
+	var result insertResult
+	for _, values := range s.values {
+		if len(values) != len(columns) {
+			return nil, fmt.Errorf("%d values are given for %d columns", len(values), len(columns))
+		}
+
+		row, err := e.table.AddNewRecord()
+		if err != nil {
+			return nil, err
+		}
+		for i, value := range values {
+			if err = e.assign(row, columns[i], value); err != nil {
+				return nil, err
+			}
+		}
+		result.lastRow = int64(row)
+		result.rowsAffected++
+	}
+	return result, nil
+}
+
+// update sets the values of the columns of the UPDATE statement, for the matching records.
+func (e *evaluator) update(s *statement) (driver.Result, error) {
+	columns := make([]int, len(s.assignments))
+	for i, a := range s.assignments {
+		var err error
+		if columns[i], err = e.columnIndex(a.column); err != nil {
+			return nil, err
+		}
+	}
+
+	matches, err := e.matchingRows(s.where)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range matches {
+		for i, a := range s.assignments {
+			if err = e.assign(row, columns[i], a.value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return driver.RowsAffected(len(matches)), nil
+}
+
+// delete marks the records matching the DELETE statement as deleted.
+func (e *evaluator) delete(s *statement) (driver.Result, error) {
+	matches, err := e.matchingRows(s.where)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range matches {
+		if err = e.table.DeleteRecord(row); err != nil {
+			return nil, err
+		}
+	}
+	return driver.RowsAffected(len(matches)), nil
+}
+
+// assign sets the value of the field with the given index for the record at the given row.
+func (e *evaluator) assign(row int, fieldIndex int, o operand) error {
+	value, err := e.value(row, o)
+	if err != nil {
+		return err
+	}
+
+	field := e.table.Fields()[fieldIndex]
+	if s, ok := value.(string); ok && isTemporal(field.FieldType()) {
+		if t, ok := parseTime(s, e.table.Location()); ok {
+			value = t
+		}
+	}
+	if err = e.table.SetTypedFieldValue(row, fieldIndex, value); err != nil {
+		return fmt.Errorf("row %d, field \"%s\": %v", row, field.Name(), err)
+	}
+	return nil
+}
+
+// insertResult is the result of an INSERT statement, whose last insert ID is the row number of the last record added.
+type insertResult struct {
+	lastRow      int64
+	rowsAffected int64
+}
+
+func (r insertResult) LastInsertId() (int64, error) {
+	return r.lastRow, nil
+}
+
+func (r insertResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+// rows are the values of the records returned by a SELECT statement.
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+	next    int
+}
+
+func (r *rows) Columns() []string {
+	return r.columns
+}
+
+func (r *rows) Close() error {
+	r.next = len(r.values)
+	return nil
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
+
+// evaluator evaluates the operands and conditions of a statement against the records of a table.
+type evaluator struct {
+	table   *godbf.DbfTable
+	args    []driver.Value
+	columns map[string]int // field indexes by upper-cased name
+}
+
+func newEvaluator(table *godbf.DbfTable, args []driver.Value) *evaluator {
+	e := &evaluator{table: table, args: args, columns: make(map[string]int)}
+	for i, field := range table.Fields() {
+		e.columns[strings.ToUpper(field.Name())] = i
+	}
+	return e
+}
+
+// columnIndex returns the index of the field of the column with the given name, regardless of case.
+func (e *evaluator) columnIndex(name string) (int, error) {
+	if i, found := e.columns[strings.ToUpper(name)]; found {
+		return i, nil
+	}
+	return 0, fmt.Errorf("column \"%s\" does not exist", name)
+}
+
+// value returns the value of the operand, for the record at the given row.
+func (e *evaluator) value(row int, o operand) (interface{}, error) {
+	switch {
+	case o.column != "":
+		i, err := e.columnIndex(o.column)
+		if err != nil {
+			return nil, err
+		}
+		return e.table.TypedFieldValue(row, i)
+	case o.placeholder > 0:
+		if o.placeholder > len(e.args) {
+			return nil, fmt.Errorf("%d arguments are given for %d placeholders", len(e.args), o.placeholder)
+		}
+		return e.args[o.placeholder-1], nil
+	}
+	return o.value, nil
+}
+
+// matchingRows returns the rows of the records that are not marked as deleted and that satisfy the condition.
+func (e *evaluator) matchingRows(where condition) (matches []int, err error) {
+	for row := 0; row < e.table.NumberOfRecords(); row++ {
+		if e.table.RowIsDeleted(row) {
+			continue
+		}
+
+		result := isTrue
+		if where != nil {
+			if result, err = e.evaluate(row, where); err != nil {
+				return
+			}
+		}
+		if result == isTrue {
+			matches = append(matches, row)
+		}
+	}
+	return
+}
+
+// evaluate evaluates the condition for the record at the given row.
+func (e *evaluator) evaluate(row int, c condition) (result truth, err error) {
+	switch c := c.(type) {
+	case comparison:
+		var left, right interface{}
+		if left, err = e.value(row, c.left); err != nil {
+			return
+		}
+		if right, err = e.value(row, c.right); err != nil {
+			return
+		}
+		return compareWith(c.operator, left, right)
+	case nullCheck:
+		var value interface{}
+		if value, err = e.value(row, c.operand); err != nil {
+			return
+		}
+		return truthOf((value == nil) != c.not), nil
+	case negation:
+		if result, err = e.evaluate(row, c.condition); err != nil {
+			return
+		}
+		return result.not(), nil
+	case logical:
+		var left, right truth
+		if left, err = e.evaluate(row, c.left); err != nil {
+			return
+		}
+		if right, err = e.evaluate(row, c.right); err != nil {
+			return
+		}
+		if c.operator == "AND" {
+			return left.and(right), nil
+		}
+		return left.or(right), nil
+	}
+	return isUnknown, fmt.Errorf("unknown condition %T", c)
+}
+
+// sortRows sorts the rows by the values of the columns of the ORDER BY clause. NULL values sort first.
+func (e *evaluator) sortRows(matches []int, orderBy []orderTerm) ([]int, error) {
+	if len(orderBy) == 0 {
+		return matches, nil
+	}
+
+	columns := make([]int, len(orderBy))
+	for i, term := range orderBy {
+		var err error
+		if columns[i], err = e.columnIndex(term.column); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make(map[int][]interface{}, len(matches))
+	for _, row := range matches {
+		key := make([]interface{}, len(columns))
+		for i, column := range columns {
+			var err error
+			if key[i], err = e.table.TypedFieldValue(row, column); err != nil {
+				return nil, err
+			}
+		}
+		keys[row] = key
+	}
+
+	var sortErr error
+	sort.SliceStable(matches, func(a, b int) bool {
+		for i, term := range orderBy {
+			x, y := keys[matches[a]][i], keys[matches[b]][i]
+			var order int
+			switch {
+			case x == nil && y == nil:
+				continue
+			case x == nil:
+				order = -1
+			case y == nil:
+				order = 1
+			default:
+				var err error
+				if order, err = compareValues(x, y); err != nil && sortErr == nil {
+					sortErr = err
+				}
+			}
+			if term.descending {
+				order = -order
+			}
+			if order != 0 {
+				return order < 0
+			}
+		}
+		return false
+	})
+	return matches, sortErr
+}
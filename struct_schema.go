@@ -0,0 +1,277 @@
+package godbf
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding"
+)
+
+const (
+	maxCharacterFieldLength = 254
+	maxNumericFieldLength   = 20
+	maxDecimalPlaces        = 15
+
+	// defaults for fields inferred from Go types
+	defaultCharacterFieldLength = maxCharacterFieldLength
+	defaultNumericFieldLength   = maxNumericFieldLength
+	defaultFloatDecimalPlaces   = 6
+)
+
+// knownFieldTypes are the field types that can be named in struct tags.
+var knownFieldTypes = []DbaseDataType{
+	Character, Logical, Date, Numeric, Float, Memo, General, Picture,
+	Integer, Currency, DateTime, Double, AutoIncrement, Timestamp, Double7,
+}
+
+var (
+	nullStringType  = reflect.TypeOf(sql.NullString{})
+	nullInt64Type   = reflect.TypeOf(sql.NullInt64{})
+	nullInt32Type   = reflect.TypeOf(sql.NullInt32{})
+	nullFloat64Type = reflect.TypeOf(sql.NullFloat64{})
+	nullBoolType    = reflect.TypeOf(sql.NullBool{})
+	nullTimeType    = reflect.TypeOf(sql.NullTime{})
+)
+
+// fieldSpec describes a field to be added to a table.
+type fieldSpec struct {
+	name          string
+	fieldType     DbaseDataType
+	length        byte
+	decimalPlaces uint8
+	nullable      bool
+}
+
+// NewFromStruct creates a new dbase table from scratch for the given character encoding, with a field for each
+// exported struct field of sample tagged with `dbf:"NAME"`, as per Unmarshal(). The sample is a struct, or a pointer
+// to one, whose values are not used.
+//
+// The type, length and decimal places of the fields are inferred from the types of the struct fields, and can be set
+// with tag options such as `dbf:"AMOUNT,type=N,len=12,dec=2"`. Strings are stored in Character fields of 254 bytes,
+// integers in Numeric fields wide enough for their range, floating point numbers and *big.Rat in Numeric fields of 20
+// digits with 6 decimal places, bools in Logical fields, time.Time in Date fields and []byte in Memo fields. Pointers,
+// and the types of database/sql such as sql.NullString, are stored as per the type they hold, in fields that are
+// nullable for Visual FoxPro tables.
+//
+// An error is returned for names that are longer than the format allows or that are used twice, for types that are
+// not known, and for lengths or decimal places that do not suit the type.
+func NewFromStruct(sample interface{}, enc encoding.Encoding, options ...TableOption) (table *DbfTable, err error) {
+	structType := reflect.TypeOf(sample)
+	if structType != nil && structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("NewFromStruct requires a struct or a pointer to a struct, not %T", sample)
+	}
+
+	table = New(enc, options...)
+	fields := taggedStructFields(structType)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%s has no fields tagged with `%s:\"NAME\"`", structType, structTagName)
+	}
+
+	for _, field := range fields {
+		var spec fieldSpec
+		if spec, err = table.fieldSpecFromStruct(field); err != nil {
+			return nil, err
+		}
+		if err = table.addField(spec.name, spec.fieldType, spec.length, spec.decimalPlaces); err != nil {
+			return nil, err
+		}
+		if spec.nullable && table.format == VisualFoxPro {
+			if err = table.SetFieldNullable(spec.name); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return
+}
+
+// fieldSpecFromStruct describes the field for a tagged struct field, as inferred from its type and set by the options
+// of its tag.
+func (dt *DbfTable) fieldSpecFromStruct(field taggedField) (spec fieldSpec, err error) {
+	spec = fieldSpec{name: field.name}
+	maxLength := dt.format.fieldDescriptorLayout().maxUsableNameByteLength()
+	if encoded, _ := dt.encodeString(spec.name); len(encoded) > maxLength {
+		return spec, fmt.Errorf("field name \"%s\" is longer than %d bytes", spec.name, maxLength)
+	}
+
+	inferred := inferFieldSpec(field.Type)
+	spec.nullable = inferred.nullable
+
+	var hasType, hasLength, hasDecimalPlaces bool
+	for _, option := range field.options {
+		key, value := option, ""
+		if i := strings.Index(option, "="); i >= 0 {
+			key, value = strings.TrimSpace(option[:i]), strings.TrimSpace(option[i+1:])
+		}
+
+		switch key {
+		case "type":
+			if spec.fieldType, err = parseFieldType(value); err != nil {
+				return spec, fmt.Errorf("%v for field \"%s\"", err, spec.name)
+			}
+			hasType = true
+		case "len":
+			if spec.length, err = parseTagByte(value); err != nil {
+				return spec, fmt.Errorf("invalid length \"%s\" for field \"%s\"", value, spec.name)
+			}
+			hasLength = true
+		case "dec":
+			if spec.decimalPlaces, err = parseTagByte(value); err != nil {
+				return spec, fmt.Errorf("invalid decimal places \"%s\" for field \"%s\"", value, spec.name)
+			}
+			hasDecimalPlaces = true
+		default:
+			return spec, fmt.Errorf("unknown option \"%s\" in tag of field \"%s\"", option, spec.name)
+		}
+	}
+
+	if !hasType {
+		if inferred.fieldType == 0 {
+			return spec, fmt.Errorf("cannot infer the type of field \"%s\" from %s, set it with type=", spec.name, field.Type)
+		}
+		spec.fieldType = inferred.fieldType
+	}
+	if !hasLength {
+		spec.length = defaultFieldLength(spec.fieldType, inferred)
+	}
+	if !hasDecimalPlaces && spec.fieldType.usesDecimalCount() && spec.fieldType == inferred.fieldType {
+		spec.decimalPlaces = inferred.decimalPlaces
+	}
+
+	if err = dt.validateFieldSpec(spec, hasLength); err == nil {
+		spec.length = dt.format.fieldLength(spec.fieldType, spec.length)
+	}
+	return
+}
+
+// validateFieldSpec verifies that the length and decimal places of the field suit its type.
+func (dt *DbfTable) validateFieldSpec(spec fieldSpec, hasLength bool) error {
+	if fixedLength := dt.format.fieldLength(spec.fieldType, 0); fixedLength != 0 {
+		if hasLength && spec.length != fixedLength {
+			return fmt.Errorf("length of field \"%s\" of type '%c' is fixed at %d", spec.name, spec.fieldType, fixedLength)
+		}
+	} else {
+		maxLength := byte(maxCharacterFieldLength)
+		if spec.fieldType != Character {
+			maxLength = maxNumericFieldLength
+		}
+		if spec.length == 0 || spec.length > maxLength {
+			return fmt.Errorf("length %d of field \"%s\" of type '%c' is not between 1 and %d",
+				spec.length, spec.name, spec.fieldType, maxLength)
+		}
+	}
+
+	switch {
+	case spec.decimalPlaces == 0:
+		return nil
+	case !spec.fieldType.usesDecimalCount():
+		return fmt.Errorf("decimal places are not applicable to field \"%s\" of type '%c'", spec.name, spec.fieldType)
+	case spec.decimalPlaces > maxDecimalPlaces:
+		return fmt.Errorf("%d decimal places of field \"%s\" exceed the maximum of %d",
+			spec.decimalPlaces, spec.name, maxDecimalPlaces)
+	case spec.fieldType != Double && int(spec.decimalPlaces) > int(spec.length)-2:
+		// room is needed for the decimal point, and a digit or sign before it
+		return fmt.Errorf("%d decimal places do not fit field \"%s\" of length %d",
+			spec.decimalPlaces, spec.name, spec.length)
+	}
+	return nil
+}
+
+// inferFieldSpec returns the type, length and decimal places of the field that suits values of the Go type given.
+// A zero fieldType is returned if there is none.
+func inferFieldSpec(t reflect.Type) (spec fieldSpec) {
+	switch t {
+	case timeType:
+		return fieldSpec{fieldType: Date}
+	case ratType:
+		return fieldSpec{fieldType: Numeric, length: defaultNumericFieldLength, decimalPlaces: defaultFloatDecimalPlaces, nullable: true}
+	case nullStringType:
+		return fieldSpec{fieldType: Character, length: defaultCharacterFieldLength, nullable: true}
+	case nullInt64Type:
+		return fieldSpec{fieldType: Numeric, length: integerFieldLength(reflect.Int64), nullable: true}
+	case nullInt32Type:
+		return fieldSpec{fieldType: Numeric, length: integerFieldLength(reflect.Int32), nullable: true}
+	case nullFloat64Type:
+		return fieldSpec{fieldType: Numeric, length: defaultNumericFieldLength, decimalPlaces: defaultFloatDecimalPlaces, nullable: true}
+	case nullBoolType:
+		return fieldSpec{fieldType: Logical, nullable: true}
+	case nullTimeType:
+		return fieldSpec{fieldType: Date, nullable: true}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		spec = inferFieldSpec(t.Elem())
+		spec.nullable = true
+	case reflect.String:
+		spec = fieldSpec{fieldType: Character, length: defaultCharacterFieldLength}
+	case reflect.Bool:
+		spec = fieldSpec{fieldType: Logical}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		spec = fieldSpec{fieldType: Numeric, length: integerFieldLength(t.Kind())}
+	case reflect.Float32, reflect.Float64:
+		spec = fieldSpec{fieldType: Numeric, length: defaultNumericFieldLength, decimalPlaces: defaultFloatDecimalPlaces}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			spec = fieldSpec{fieldType: Memo}
+		}
+	}
+	return
+}
+
+// integerFieldLength returns the length of a Numeric field that holds any value of the integer kind given, sign
+// included.
+func integerFieldLength(kind reflect.Kind) byte {
+	switch kind {
+	case reflect.Int8:
+		return 4
+	case reflect.Int16:
+		return 6
+	case reflect.Int32:
+		return 11
+	case reflect.Uint8:
+		return 3
+	case reflect.Uint16:
+		return 5
+	case reflect.Uint32:
+		return 10
+	}
+	return maxNumericFieldLength
+}
+
+// defaultFieldLength returns the length of a field of the given type, when the tag does not set one.
+func defaultFieldLength(fieldType DbaseDataType, inferred fieldSpec) byte {
+	switch {
+	case fieldType == inferred.fieldType && inferred.length != 0:
+		return inferred.length
+	case fieldType == Character:
+		return defaultCharacterFieldLength
+	case fieldType.oneOf(Numeric, Float):
+		return defaultNumericFieldLength
+	}
+	return 0
+}
+
+// parseFieldType parses the letter of a field type, such as "N" for Numeric.
+func parseFieldType(value string) (DbaseDataType, error) {
+	if len(value) == 1 {
+		for _, fieldType := range knownFieldTypes {
+			if fieldType.byte() == strings.ToUpper(value)[0] {
+				return fieldType, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unknown field type \"%s\"", value)
+}
+
+// parseTagByte parses a length or number of decimal places of a tag.
+func parseTagByte(value string) (byte, error) {
+	b, err := strconv.ParseUint(value, 10, 8)
+	return byte(b), err
+}
@@ -0,0 +1,155 @@
+package godbf
+
+import (
+	"database/sql"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+)
+
+type schemaInvoice struct {
+	marshalAudit
+	Number   string          `dbf:"NUMBER,len=12"`
+	Customer string          `dbf:"customer"`
+	Amount   float64         `dbf:"AMOUNT,type=N,len=12,dec=2"`
+	Exact    *big.Rat        `dbf:"EXACT,len=14,dec=4"`
+	Lines    int16           `dbf:"LINES"`
+	Issued   time.Time       `dbf:"ISSUED"`
+	Paid     *bool           `dbf:"PAID"`
+	Note     sql.NullString  `dbf:"NOTE,type=M"`
+	Rate     float32         `dbf:"RATE,type=F,len=8,dec=3"`
+	Untagged map[string]bool // untagged fields are ignored
+	Named    int             `dbf:",len=3"`
+}
+
+func TestNewFromStruct(t *testing.T) {
+	table, err := NewFromStruct(&schemaInvoice{}, charmap.CodePage866, WithFormat(DBaseIV))
+	require.Nil(t, err)
+	require.Equal(t, table.Format(), DBaseIV)
+	require.EqualValues(t, table.dataStore[0], 0x8B)
+
+	type expectedField struct {
+		name          string
+		fieldType     DbaseDataType
+		length        byte
+		decimalPlaces byte
+	}
+	expected := []expectedField{
+		{"CHECKED", Logical, 1, 0},
+		{"NUMBER", Character, 12, 0},
+		{"customer", Character, 254, 0},
+		{"AMOUNT", Numeric, 12, 2},
+		{"EXACT", Numeric, 14, 4},
+		{"LINES", Numeric, 6, 0},
+		{"ISSUED", Date, 8, 0},
+		{"PAID", Logical, 1, 0},
+		{"NOTE", Memo, 10, 0},
+		{"RATE", Float, 8, 3},
+		{"NAMED", Numeric, 3, 0},
+	}
+	fields := table.Fields()
+	require.Len(t, fields, len(expected))
+	for i, field := range fields {
+		require.Equal(t, expectedField{field.Name(), field.FieldType(), field.Length(), field.DecimalPlaces()}, expected[i])
+	}
+
+	paid := true
+	invoice := schemaInvoice{
+		Number:   "INV-001",
+		Customer: "Иван",
+		Amount:   99.999,
+		Exact:    big.NewRat(1, 8),
+		Lines:    -3,
+		Issued:   time.Date(2024, time.February, 29, 0, 0, 0, 0, time.Local),
+		Paid:     &paid,
+		Note:     sql.NullString{String: "примечание", Valid: true},
+		Rate:     0.125,
+		Named:    7,
+	}
+	_, err = AppendStruct(table, invoice)
+	require.Nil(t, err)
+
+	var loaded schemaInvoice
+	require.Nil(t, Unmarshal(table, 0, &loaded))
+	invoice.Amount = 100
+	require.Equal(t, loaded, invoice)
+}
+
+func TestNewFromStruct_VisualFoxProNullableFields(t *testing.T) {
+	var sample struct {
+		Name  string        `dbf:"NAME,len=10"`
+		Count *int32        `dbf:"COUNT"`
+		Total sql.NullInt64 `dbf:"TOTAL"`
+		When  *time.Time    `dbf:"WHEN,type=T"`
+	}
+	table, err := NewFromStruct(sample, nil, WithFormat(VisualFoxPro))
+	require.Nil(t, err)
+
+	fields := table.Fields()
+	require.Len(t, fields, 4)
+	require.False(t, fields[0].IsNullable())
+	require.True(t, fields[1].IsNullable())
+	require.True(t, fields[2].IsNullable())
+	require.True(t, fields[3].IsNullable())
+	require.Equal(t, fields[3].FieldType(), DateTime)
+	require.EqualValues(t, fields[1].Length(), 11)
+	require.Len(t, table.AllFields(), 5)
+
+	_, err = AppendStruct(table, sample)
+	require.Nil(t, err)
+	require.True(t, table.FieldIsNull(0, 1))
+	require.True(t, table.FieldIsNull(0, 3))
+}
+
+func TestNewFromStruct_InvalidTags(t *testing.T) {
+	for _, test := range []struct {
+		sample   interface{}
+		expected string
+	}{
+		{42, "NewFromStruct requires a struct or a pointer to a struct, not int"},
+		{struct{ Name string }{}, "struct { Name string } has no fields tagged with `dbf:\"NAME\"`"},
+		{struct {
+			Name string `dbf:"VERY_LONG_NAME"`
+		}{}, "field name \"VERY_LONG_NAME\" is longer than 10 bytes"},
+		{struct {
+			Name  string `dbf:"NAME"`
+			Other string `dbf:"NAME"`
+		}{}, "Field name \"NAME\" already exists"},
+		{struct {
+			Name string `dbf:"NAME,dec=2"`
+		}{}, "decimal places are not applicable to field \"NAME\" of type 'C'"},
+		{struct {
+			Amount float64 `dbf:"AMOUNT,len=5,dec=4"`
+		}{}, "4 decimal places do not fit field \"AMOUNT\" of length 5"},
+		{struct {
+			Name string `dbf:"NAME,len=300"`
+		}{}, "invalid length \"300\" for field \"NAME\""},
+		{struct {
+			Amount int `dbf:"AMOUNT,len=21"`
+		}{}, "length 21 of field \"AMOUNT\" of type 'N' is not between 1 and 20"},
+		{struct {
+			Born time.Time `dbf:"BORN,len=10"`
+		}{}, "length of field \"BORN\" of type 'D' is fixed at 8"},
+		{struct {
+			Name string `dbf:"NAME,type=X"`
+		}{}, "unknown field type \"X\" for field \"NAME\""},
+		{struct {
+			Name string `dbf:"NAME,width=3"`
+		}{}, "unknown option \"width=3\" in tag of field \"NAME\""},
+		{struct {
+			Tags []string `dbf:"TAGS"`
+		}{}, "cannot infer the type of field \"TAGS\" from []string, set it with type="},
+	} {
+		_, err := NewFromStruct(test.sample, nil)
+		require.EqualError(t, err, test.expected)
+	}
+
+	// dBase 7 allows longer names
+	_, err := NewFromStruct(struct {
+		Name string `dbf:"VERY_LONG_NAME"`
+	}{}, nil, WithFormat(DBase7))
+	require.Nil(t, err)
+}
@@ -18,6 +18,11 @@ const (
 	maxUsableNameByteLength      = fieldNameByteLength - 1
 	endOfFieldNameMarker    byte = 0x0
 
+	fieldDescriptorLength       = 32
+	fieldDisplacementIndex      = 12
+	fieldFlagsIndex             = 18
+	fieldTerminatorMarker  byte = 0x0D
+
 	recordDeletionFlagIndex = 0
 	recordIsActive          = blank
 	recordIsDeleted         = 0x2A
@@ -55,8 +60,10 @@ type tableManagement struct {
 	fieldMap       map[string]int // used to map field names to index
 
 	schemaLockable
-	createdFromScratch bool // used before adding new fields to increment nu
+	createdFromScratch bool   // used before adding new fields to increment nu
+	format             Format // xBase dialect of the table
 	encodingSupport
+	memoSupport
 }
 
 // schemaLockable permits or denys updates to the database field-definitions. You can only add new records when the
@@ -67,12 +74,20 @@ type schemaLockable struct {
 
 // encoding provides text encoding support for DbfTable
 type encodingSupport struct {
-	decoder *encoding.Decoder
-	encoder *encoding.Encoder
+	encoding encoding.Encoding
+	decoder  *encoding.Decoder
+	encoder  *encoding.Encoder
+}
+
+// Encoding returns the character encoding of the table, as supplied or as detected via Auto(). Nil is returned if
+// character data is passed on without decoding.
+func (es *encodingSupport) Encoding() encoding.Encoding {
+	return es.encoding
 }
 
 // useEncoding setsets encoding
 func (es *encodingSupport) useEncoding(enc encoding.Encoding) {
+	es.encoding = enc
 	if enc != nil {
 		es.encoder = enc.NewEncoder()
 		es.decoder = enc.NewDecoder()
@@ -81,7 +96,8 @@ func (es *encodingSupport) useEncoding(enc encoding.Encoding) {
 
 // imageCache keeps a dbase table in memory as its byte array encoding
 type imageCache struct {
-	dataStore []byte
+	dataStore          []byte
+	hasEndOfFileMarker bool // the table was loaded with a trailing end-of-file marker
 }
 
 func (dt *DbfTable) AddBooleanField(fieldName string) (err error) {
@@ -92,6 +108,21 @@ func (dt *DbfTable) AddDateField(fieldName string) (err error) {
 	return dt.addField(fieldName, Date, Date.fixedFieldLength(), Date.decimalCountNotApplicable())
 }
 
+// AddMemoField adds a field whose values are kept in the table's memo file, which is created alongside the table.
+func (dt *DbfTable) AddMemoField(fieldName string) (err error) {
+	return dt.addField(fieldName, Memo, dt.format.memoBlockPointerLength(), Memo.decimalCountNotApplicable())
+}
+
+// AddGeneralField adds a FoxPro General (OLE object) field, kept in the table's memo file as binary data.
+func (dt *DbfTable) AddGeneralField(fieldName string) (err error) {
+	return dt.addField(fieldName, General, dt.format.memoBlockPointerLength(), General.decimalCountNotApplicable())
+}
+
+// AddPictureField adds a FoxPro Picture field, kept in the table's memo file as binary data.
+func (dt *DbfTable) AddPictureField(fieldName string) (err error) {
+	return dt.addField(fieldName, Picture, dt.format.memoBlockPointerLength(), Picture.decimalCountNotApplicable())
+}
+
 func (dt *DbfTable) AddTextField(fieldName string, length byte) (err error) {
 	return dt.addField(fieldName, Character, length, Character.decimalCountNotApplicable())
 }
@@ -104,6 +135,56 @@ func (dt *DbfTable) AddFloatField(fieldName string, length byte, decimalPlaces u
 	return dt.addField(fieldName, Float, length, decimalPlaces)
 }
 
+// AddIntegerField adds a Visual FoxPro Integer field, storing 32-bit signed integers.
+func (dt *DbfTable) AddIntegerField(fieldName string) (err error) {
+	return dt.addField(fieldName, Integer, Integer.fixedFieldLength(), Integer.decimalCountNotApplicable())
+}
+
+// AddCurrencyField adds a Visual FoxPro Currency field, storing exact amounts with 4 decimal places.
+func (dt *DbfTable) AddCurrencyField(fieldName string) (err error) {
+	return dt.addField(fieldName, Currency, Currency.fixedFieldLength(), Currency.decimalCountNotApplicable())
+}
+
+// AddDateTimeField adds a Visual FoxPro DateTime field, storing a date and time of day to the millisecond.
+func (dt *DbfTable) AddDateTimeField(fieldName string) (err error) {
+	return dt.addField(fieldName, DateTime, DateTime.fixedFieldLength(), DateTime.decimalCountNotApplicable())
+}
+
+// AddDoubleField adds a Visual FoxPro Double field, storing 64-bit floating point numbers. The decimal places are
+// only a display hint for xBase applications, the full precision of values is always stored.
+func (dt *DbfTable) AddDoubleField(fieldName string, decimalPlaces uint8) (err error) {
+	return dt.addField(fieldName, Double, Double.fixedFieldLength(), decimalPlaces)
+}
+
+// AddAutoIncrementField adds a dBase 7 Autoincrement field, which is assigned the next value in sequence whenever a
+// new record is added, starting from 1.
+func (dt *DbfTable) AddAutoIncrementField(fieldName string) (err error) {
+	return dt.addField(fieldName, AutoIncrement, AutoIncrement.fixedFieldLength(), AutoIncrement.decimalCountNotApplicable())
+}
+
+// AddTimestampField adds a dBase 7 Timestamp field, storing a date and time of day to the millisecond.
+func (dt *DbfTable) AddTimestampField(fieldName string) (err error) {
+	return dt.addField(fieldName, Timestamp, Timestamp.fixedFieldLength(), Timestamp.decimalCountNotApplicable())
+}
+
+// AddDouble7Field adds a dBase 7 Double field, storing 64-bit floating point numbers.
+func (dt *DbfTable) AddDouble7Field(fieldName string) (err error) {
+	return dt.addField(fieldName, Double7, Double7.fixedFieldLength(), Double7.decimalCountNotApplicable())
+}
+
+// addRawField adds a field of a type that is not understood, whose values are only available as raw bytes.
+func (dt *DbfTable) addRawField(fieldName string, fieldType DbaseDataType, length byte, decimalPlaces uint8) (err error) {
+	fieldIndex := len(dt.fields)
+	if dt.keepsNullFlagsFieldLast(fieldType) {
+		fieldIndex--
+	}
+
+	if err = dt.addField(fieldName, fieldType, length, decimalPlaces); err == nil {
+		dt.fields[fieldIndex].raw = true
+	}
+	return
+}
+
 func (dt *DbfTable) addField(fieldName string, fieldType DbaseDataType, length byte, decimalPlaces uint8) (err error) {
 	if dt.schemaLocked {
 		return errors.New("Once you start entering data to the dbase table or open an existing dbase file, altering dbase table schema is not allowed!")
@@ -124,21 +205,16 @@ func (dt *DbfTable) addField(fieldName string, fieldType DbaseDataType, length b
 	df.length = length
 	df.decimalPlaces = decimalPlaces
 
+	layout := dt.format.fieldDescriptorLayout()
+	df.fieldStore = make([]byte, layout.descriptorLength)
+
 	var slice []byte
-	if slice, err = dt.convertToByteSlice(df.name, fieldNameByteLength); err != nil {
+	if slice, err = dt.convertToByteSlice(df.name, layout.maxUsableNameByteLength()); err != nil {
 		return
 	}
 
-	// Field name in ASCII (max 10 chracters)
-	end := len(slice)
-	if end > 32 {
-		end = 32
-	}
-	for i := 0; i < end; i++ {
-		df.fieldStore[i] = slice[i]
-	}
-
-	df.fieldStore[fieldNameByteLength] = endOfFieldNameMarker
+	// Field name in ASCII (max 10 chracters, or 31 for dBase 7), padded with end of field name markers
+	copy(df.fieldStore, slice)
 
 	// Set field's data type
 	// C (Character)  All OEM code page characters.
@@ -146,21 +222,39 @@ func (dt *DbfTable) addField(fieldName string, fieldType DbaseDataType, length b
 	// N (Numeric)    - . 0 1 2 3 4 5 6 7 8 9
 	// F (Floating Point)   - . 0 1 2 3 4 5 6 7 8 9
 	// L (Logical)    ? Y y N n T t F f (? when not initialized).
-	df.fieldStore[11] = df.fieldType.byte()
+	df.fieldStore[layout.typeIndex] = df.fieldType.byte()
 
 	// fixedFieldLength of field
-	df.fieldStore[16] = df.length
+	df.fieldStore[layout.lengthIndex] = df.length
 
 	// number of decimal places
 	// Applicable only to number/float
-	df.fieldStore[17] = df.decimalPlaces
+	df.fieldStore[layout.decimalIndex] = df.decimalPlaces
+
+	// dBase 7 autoincrement fields start counting at 1
+	if fieldType == AutoIncrement && dt.format == DBase7 {
+		copy(df.fieldStore[dBase7AutoIncrementNextValueIndex:], uint32ToBytes(1))
+	}
 
 	//fmt.Printf("addField | append:%v\n", df)
 
-	dt.fields = append(dt.fields, *df)
+	if dt.keepsNullFlagsFieldLast(fieldType) {
+		// Visual FoxPro expects the _NullFlags system field after all others
+		nullFlags := dt.fields[len(dt.fields)-1]
+		dt.fields = append(dt.fields[:len(dt.fields)-1], *df, nullFlags)
+	} else {
+		dt.fields = append(dt.fields, *df)
+	}
 
 	// if createdFromScratch we need to update dbase header to reflect the changes we have made
 	if dt.createdFromScratch {
+		if fieldType.usesMemo() && dt.memo == nil {
+			dt.memo = dt.format.newMemoFile()
+			dt.fileSignature = dt.format.signature(true)
+			if dt.format == VisualFoxPro {
+				dt.dataStore[tableFlagsIndex] |= vfpTableHasMemoFlag
+			}
+		}
 		dt.updateHeader()
 	}
 
@@ -173,8 +267,8 @@ func (dt *DbfTable) normaliseFieldName(name string) (s string, err error) {
 	}
 
 	b := []byte(name)
-	if len(b) > maxUsableNameByteLength {
-		b = b[0:maxUsableNameByteLength]
+	if maxLength := dt.format.fieldDescriptorLayout().maxUsableNameByteLength(); len(b) > maxLength {
+		b = b[0:maxLength]
 	}
 
 	b, err = dt.decodeBytes(b)
@@ -204,25 +298,37 @@ func (dt *DbfTable) convertToByteSlice(value string, numberOfBytes int) (s []byt
 }
 
 func (dt *DbfTable) updateHeader() {
-	// first create a slice from initial 32 bytes of datastore as the foundation of the new slice
+	// keep hold of the backlink area, if any, before the header is rebuilt
+	backlink := make([]byte, dt.format.backlinkLength())
+	copy(backlink, dt.backlinkArea())
+
+	// first create a slice from initial 32 bytes (68 for dBase 7) of datastore as the foundation of the new slice
 	// later we will set this slice to dt.dataStore to create the new header slice
-	slice := dt.dataStore[0:32]
+	slice := dt.dataStore[0:dt.format.headerPrefixLength()]
 
 	// set dbase file signature
-	slice[0] = 0x03
+	slice[0] = dt.fileSignature
 
 	var lengthOfEachRecord uint16 = 0
 
-	for i := range dt.Fields() {
-		lengthOfEachRecord += uint16(dt.Fields()[i].length)
-		slice = append(slice, dt.Fields()[i].fieldStore[:]...)
+	for i := range dt.fields {
+		if dt.format == VisualFoxPro {
+			// Visual FoxPro records the displacement of the field within the record
+			copy(dt.fields[i].fieldStore[fieldDisplacementIndex:], uint32ToBytes(uint32(lengthOfEachRecord)+1))
+		}
+
+		lengthOfEachRecord += uint16(dt.fields[i].length)
+		slice = append(slice, dt.fields[i].fieldStore...)
 
 		// don't forget to update fieldMap. We need it to find the index of a field name
-		dt.fieldMap[dt.Fields()[i].name] = i
+		dt.fieldMap[dt.fields[i].name] = i
 	}
 
 	// end of file header terminator (0Dh)
-	slice = append(slice, 0x0D)
+	slice = append(slice, fieldTerminatorMarker)
+
+	// Visual FoxPro backlink to the database container
+	slice = append(slice, backlink...)
 
 	// now reset dt.dataStore slice with the updated one
 	dt.dataStore = slice
@@ -241,8 +347,66 @@ func (dt *DbfTable) updateHeader() {
 	dt.dataStore[11] = s[1]
 }
 
+// backlinkArea returns the header bytes reserved for the Visual FoxPro backlink, which follow the field terminator.
+// Nil is returned for formats without a backlink.
+func (dt *DbfTable) backlinkArea() []byte {
+	length := dt.format.backlinkLength()
+	end := int(dt.numberOfBytesInHeader)
+	if length == 0 || end-length < dt.format.headerPrefixLength() || end > len(dt.dataStore) {
+		return nil
+	}
+	return dt.dataStore[end-length : end]
+}
+
+// Backlink returns the path of the database container (.DBC) a Visual FoxPro table belongs to, as stored in its
+// header. An empty string is returned for free tables, and for other formats.
+func (dt *DbfTable) Backlink() string {
+	b := dt.backlinkArea()
+	if end := bytes.IndexByte(b, null); end >= 0 {
+		b = b[:end]
+	}
+
+	s, err := dt.decodeBytes(b)
+	if err != nil {
+		return ""
+	}
+	return string(s)
+}
+
+// SetBacklink sets the path of the database container (.DBC) a Visual FoxPro table belongs to.
+// An error is returned for other formats, or if the path does not fit the 263 bytes reserved for it.
+func (dt *DbfTable) SetBacklink(path string) (err error) {
+	area := dt.backlinkArea()
+	if area == nil {
+		return errors.New("only Visual FoxPro tables have a backlink")
+	}
+
+	var es string
+	if es, err = dt.encodeString(path); err != nil {
+		return
+	}
+	if len(es) > len(area) {
+		return fmt.Errorf("backlink of %d bytes exceeds the %d bytes available", len(es), len(area))
+	}
+
+	clearBytes(area)
+	copy(area, es)
+	return
+}
+
 // Fields return the fields of the table as a slice
+// System fields, such as the _NullFlags field that Visual FoxPro places after all others, are omitted; use AllFields()
+// to include them. Field indexes are the same for both.
 func (dt *DbfTable) Fields() []FieldDescriptor {
+	n := len(dt.fields)
+	for n > 0 && dt.fields[n-1].IsSystem() {
+		n--
+	}
+	return dt.fields[:n]
+}
+
+// AllFields returns all fields of the table, including system fields hidden from Fields().
+func (dt *DbfTable) AllFields() []FieldDescriptor {
 	return dt.fields
 }
 
@@ -282,7 +446,7 @@ func (dt *DbfTable) DecimalPlacesInField(fieldName string) (uint8, error) {
 		}
 	}
 
-	return 0, fmt.Errorf("type of field \"%s\" is not Numeric or Float.", fieldName)
+	return 0, fmt.Errorf("type of field \"%s\" is not Numeric, Float or Double.", fieldName)
 }
 
 // AddNewRecord adds a new empty record to the table, and returns the index number of the record.
@@ -293,24 +457,35 @@ func (dt *DbfTable) AddNewRecord() (newRecordNumber int, addErr error) {
 
 	dt.schemaLocked = true
 
-	newRecord := make([]byte, dt.lengthOfEachRecord)
-	newRecord[recordDeletionFlagIndex] = recordIsActive
-	dt.dataStore = append(dt.dataStore, newRecord...)
+	dt.dataStore = append(dt.dataStore, dt.emptyRecord()...)
+	dt.assignAutoIncrementValues(dt.record(int(dt.numberOfRecords)))
 
 	// since row numbers are "0" based first we set newRecordNumber
 	// and then increment number of records in dbase table
 	newRecordNumber = int(dt.numberOfRecords)
 
 	//fmt.Printf("Number of rows before:%d\n", dt.numberOfRecords)
-	dt.numberOfRecords++
+	dt.setNumberOfRecords(dt.numberOfRecords + 1)
+	//fmt.Printf("Number of rows after:%d\n", dt.numberOfRecords)
+
+	return newRecordNumber, nil
+}
+
+// setNumberOfRecords sets the number of records in the table, updating the count in the header to match.
+func (dt *DbfTable) setNumberOfRecords(numberOfRecords uint32) {
+	dt.numberOfRecords = numberOfRecords
 	s := uint32ToBytes(dt.numberOfRecords)
 	dt.dataStore[4] = s[0]
 	dt.dataStore[5] = s[1]
 	dt.dataStore[6] = s[2]
 	dt.dataStore[7] = s[3]
-	//fmt.Printf("Number of rows after:%d\n", dt.numberOfRecords)
+}
 
-	return newRecordNumber, nil
+// emptyRecord returns the bytes of a new, active record with no field values set.
+func (dt *DbfTable) emptyRecord() []byte {
+	record := make([]byte, dt.lengthOfEachRecord)
+	record[recordDeletionFlagIndex] = recordIsActive
+	return record
 }
 
 // NumberOfRecords returns the number of records in the table
@@ -336,7 +511,34 @@ func (dt *DbfTable) SetFieldValueByName(row int, fieldName string, value string)
 
 // SetFieldValue sets the value for the given row and field index as specified
 // If the field index is invalid, or the value is incompatible with the field's type, an error is returned.
+// The memo of a memo field is written over its previous blocks where it fits in them, or appended to the memo file
+// otherwise, which then grows, the previous blocks being left unused until the table is packed.
 func (dt *DbfTable) SetFieldValue(row int, fieldIndex int, value string) (err error) {
+	return dt.setFieldValueInRecord(dt.record(row), fieldIndex, value)
+}
+
+// setFieldValueInRecord encodes the value for the field with the given index into the bytes of a single record.
+func (dt *DbfTable) setFieldValueInRecord(record []byte, fieldIndex int, value string) (err error) {
+	if dt.fields[fieldIndex].raw {
+		return fmt.Errorf("type '%c' of field \"%s\" is not supported, use SetRawFieldValue() instead",
+			dt.fields[fieldIndex].fieldType, dt.fields[fieldIndex].name)
+	}
+
+	defer func() {
+		if err == nil {
+			dt.clearNullFlag(record, fieldIndex)
+		}
+	}()
+
+	if dt.fields[fieldIndex].fieldType.usesMemo() {
+		return dt.setMemoValueInRecord(record, fieldIndex, value)
+	}
+
+	if dt.fields[fieldIndex].fieldType.isBinary() {
+		offset := dt.fieldOffset(fieldIndex)
+		return dt.setBinaryFieldValue(dt.fields[fieldIndex].fieldType, record[offset:offset+int(dt.fields[fieldIndex].length)], value)
+	}
+
 	var es string
 	if es, err = dt.encodeString(value); err != nil {
 		return
@@ -345,40 +547,22 @@ func (dt *DbfTable) SetFieldValue(row int, fieldIndex int, value string) (err er
 	b := []byte(es)
 	fieldLength := int(dt.fields[fieldIndex].length)
 
-	//DEBUG
-
-	//fmt.Printf("dt.numberOfBytesInHeader=%v\n\n", dt.numberOfBytesInHeader)
-	//fmt.Printf("dt.lengthOfEachRecord=%v\n\n", dt.lengthOfEachRecord)
-
-	// locate the offset of the field in DbfTable dataStore
-	offset := int(dt.numberOfBytesInHeader)
-	lengthOfRecord := int(dt.lengthOfEachRecord)
-
-	offset = offset + (row * lengthOfRecord)
-
-	recordOffset := 1
-
-	for i := 0; i < len(dt.fields); i++ {
-		if i == fieldIndex {
-			break
-		} else {
-			recordOffset += int(dt.fields[i].length)
-		}
-	}
+	// locate the offset of the field in the record
+	recordOffset := dt.fieldOffset(fieldIndex)
+	field := record[recordOffset : recordOffset+fieldLength]
 
-	dt.fillFieldWithBlanks(fieldLength, offset, recordOffset)
+	fillFieldWithBlanks(field)
 
 	// write new value
 	switch dt.fields[fieldIndex].fieldType {
 	case Character, Logical, Date:
 		for i := 0; i < len(b) && i < fieldLength; i++ {
-			dt.dataStore[offset+recordOffset+i] = b[i]
+			field[i] = b[i]
 		}
 	case Float, Numeric:
 		for i := 0; i < fieldLength; i++ {
-			// fmt.Printf("i:%v\n", i)
 			if i < len(b) {
-				dt.dataStore[offset+recordOffset+(fieldLength-i-1)] = b[(len(b)-1)-i]
+				field[fieldLength-i-1] = b[(len(b)-1)-i]
 			} else {
 				break
 			}
@@ -386,38 +570,38 @@ func (dt *DbfTable) SetFieldValue(row int, fieldIndex int, value string) (err er
 	}
 
 	return
-
-	//fmt.Printf("field value:%#v\n", []byte(value))
-	//fmt.Printf("field index:%#v\n", fieldIndex)
-	//fmt.Printf("field fixedFieldLength:%v\n", dt.Fields[fieldIndex].fixedFieldLength)
-	//fmt.Printf("string to byte:%#v\n", b)
 }
 
-func (dt *DbfTable) fillFieldWithBlanks(fieldLength int, offset int, recordOffset int) {
-	for i := 0; i < fieldLength; i++ {
-		dt.dataStore[offset+recordOffset+i] = blank
+func fillFieldWithBlanks(field []byte) {
+	for i := range field {
+		field[i] = blank
 	}
 }
 
 //FieldValue returns the content for the record at the given row and field index as a string
 // If the row or field index is invalid, an error is returned .
+// For memo fields the content of the memo is returned, or an empty string if it cannot be read; use MemoFieldValue()
+// to find out why. Fields of types that are not understood are interpreted as text, on a best effort basis; use
+// RawFieldValue() to get at their content as is.
 func (dt *DbfTable) FieldValue(row int, fieldIndex int) (value string) {
-	offset := int(dt.numberOfBytesInHeader)
-	lengthOfRecord := int(dt.lengthOfEachRecord)
+	return dt.fieldValueFromRecord(dt.record(row), fieldIndex)
+}
 
-	offset = offset + (row * lengthOfRecord)
+// fieldValueFromRecord decodes the value of the field with the given index from the bytes of a single record.
+func (dt *DbfTable) fieldValueFromRecord(record []byte, fieldIndex int) string {
+	if dt.fields[fieldIndex].fieldType.usesMemo() {
+		value, _ := dt.memoValueFromRecord(record, fieldIndex)
+		return value
+	}
 
-	recordOffset := 1
+	offset := dt.fieldOffset(fieldIndex)
 
-	for i := 0; i < len(dt.fields); i++ {
-		if i == fieldIndex {
-			break
-		} else {
-			recordOffset += int(dt.fields[i].length)
-		}
+	if dt.fields[fieldIndex].fieldType.isBinary() {
+		return dt.binaryFieldValue(dt.fields[fieldIndex].fieldType, record[offset:offset+int(dt.fields[fieldIndex].length)])
 	}
 
-	temp := dt.dataStore[(offset + recordOffset):((offset + recordOffset) + int(dt.fields[fieldIndex].length))]
+	temp := make([]byte, dt.fields[fieldIndex].length)
+	copy(temp, record[offset:offset+len(temp)])
 
 	enforceBlankPadding(temp)
 
@@ -425,15 +609,63 @@ func (dt *DbfTable) FieldValue(row int, fieldIndex int) (value string) {
 	if temp, err = dt.decodeBytes(temp); err != nil {
 		return ""
 	}
-	//fmt.Printf("utf-8 value:[%#v] original value:[%#v]\n", s, string(temp))
-
-	//fmt.Printf("raw value:[%#v]\n", dt.dataStore[(offset + recordOffset):((offset + recordOffset) + int(dt.Fields[fieldIndex].fixedFieldLength))])
-	//fmt.Printf("utf-8 value:[%#v]\n", []byte(s))
-	//value = string(dt.dataStore[(offset + recordOffset):((offset + recordOffset) + int(dt.Fields[fieldIndex].fixedFieldLength))])
 
 	return string(bytes.TrimSpace(temp))
 }
 
+// record returns the bytes of the record at the given row, deletion flag included.
+func (dt *DbfTable) record(row int) []byte {
+	offset := dt.recordOffset(row)
+	return dt.dataStore[offset : offset+int(dt.lengthOfEachRecord)]
+}
+
+// recordOffset returns the position of the record at the given row within dataStore.
+func (dt *DbfTable) recordOffset(row int) int {
+	return int(dt.numberOfBytesInHeader) + row*int(dt.lengthOfEachRecord)
+}
+
+// fieldOffset returns the position of the field with the given index within a record.
+// The first field starts at 1, straight after the deletion flag.
+func (dt *DbfTable) fieldOffset(fieldIndex int) int {
+	offset := 1
+	for i := 0; i < fieldIndex; i++ {
+		offset += int(dt.fields[i].length)
+	}
+	return offset
+}
+
+// RawFieldValue returns the bytes stored for the record at the given row and field index, exactly as they are stored.
+// This gives access to the content of fields of any type, including types that are not otherwise supported.
+func (dt *DbfTable) RawFieldValue(row int, fieldIndex int) []byte {
+	offset := dt.fieldOffset(fieldIndex)
+	value := make([]byte, dt.fields[fieldIndex].length)
+	copy(value, dt.record(row)[offset:])
+	return value
+}
+
+// RawFieldValueByName returns the bytes stored for the field of the given row number and name, exactly as they are
+// stored.
+func (dt *DbfTable) RawFieldValueByName(row int, fieldName string) (value []byte, err error) {
+	if fieldIndex, entryFound := dt.fieldMap[fieldName]; entryFound {
+		return dt.RawFieldValue(row, fieldIndex), err
+	}
+	err = fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+	return
+}
+
+// SetRawFieldValue stores the bytes supplied for the record at the given row and field index, exactly as they are.
+// The value must be as long as the field. No check is made that it is valid for the field's type.
+func (dt *DbfTable) SetRawFieldValue(row int, fieldIndex int, value []byte) error {
+	if len(value) != int(dt.fields[fieldIndex].length) {
+		return fmt.Errorf("raw value of %d bytes does not match the length of field \"%s\", %d bytes",
+			len(value), dt.fields[fieldIndex].name, dt.fields[fieldIndex].length)
+	}
+
+	offset := dt.fieldOffset(fieldIndex)
+	copy(dt.record(row)[offset:], value)
+	return nil
+}
+
 // Some Dbf encoders pad with null chars instead of blanks, this forces blanks as per
 // https://www.dbase.com/Knowledgebase/INT/db7_file_fmt.htm
 func enforceBlankPadding(temp []byte) {
@@ -473,10 +705,12 @@ func (dt *DbfTable) FieldValueByName(row int, fieldName string) (value string, e
 
 //RowIsDeleted returns whether a row has marked as deleted
 func (dt *DbfTable) RowIsDeleted(row int) bool {
-	offset := int(dt.numberOfBytesInHeader)
-	lengthOfRecord := int(dt.lengthOfEachRecord)
-	offset = offset + (row * lengthOfRecord)
-	return dt.dataStore[offset:(offset + 1)][recordDeletionFlagIndex] == recordIsDeleted
+	return recordIsMarkedDeleted(dt.record(row))
+}
+
+// recordIsMarkedDeleted returns whether the deletion flag of the record bytes supplied is set.
+func recordIsMarkedDeleted(record []byte) bool {
+	return record[recordDeletionFlagIndex] == recordIsDeleted
 }
 
 // GetRowAsSlice return the record values for the row specified as a string slice
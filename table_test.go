@@ -410,3 +410,83 @@ func TestDbfTable_FieldDescriptor(t *testing.T) {
 	require.Equal(t, fieldUnderTest.DecimalPlaces(), decimalPlaces)
 	require.Equal(t, fieldUnderTest.Name(), fieldName)
 }
+
+func TestDbfTable_UnknownFieldType_KeptAsRawField(t *testing.T) {
+	table := New(nil)
+	require.Nil(t, table.AddTextField("BEFORE", 5))
+	require.Nil(t, table.AddTextField("UNKNOWN", 4))
+	require.Nil(t, table.AddTextField("AFTER", 5))
+
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Nil(t, table.SetFieldValueByName(row, "BEFORE", "one"))
+	require.Nil(t, table.SetRawFieldValue(row, 1, []byte{0x00, 0x01, 0x02, 0xFF}))
+	require.Nil(t, table.SetFieldValueByName(row, "AFTER", "three"))
+
+	// Pretend the middle field is of a type from some other xBase dialect.
+	data := make([]byte, len(table.dataStore))
+	copy(data, table.dataStore)
+	data[32+fieldDescriptorLength+11] = 'X'
+
+	tableUnderTest, err := NewFromByteArray(data, nil)
+	require.Nil(t, err)
+	require.Equal(t, tableUnderTest.FieldNames(), []string{"BEFORE", "UNKNOWN", "AFTER"})
+
+	unknownField := tableUnderTest.Fields()[1]
+	require.True(t, unknownField.IsRaw())
+	require.EqualValues(t, unknownField.FieldType(), 'X')
+	require.EqualValues(t, unknownField.Length(), 4)
+	require.False(t, tableUnderTest.Fields()[2].IsRaw())
+
+	value, err := tableUnderTest.FieldValueByName(row, "AFTER")
+	require.Nil(t, err)
+	require.Equal(t, value, "three")
+
+	raw, err := tableUnderTest.RawFieldValueByName(row, "UNKNOWN")
+	require.Nil(t, err)
+	require.Equal(t, raw, []byte{0x00, 0x01, 0x02, 0xFF})
+
+	err = tableUnderTest.SetFieldValueByName(row, "UNKNOWN", "text")
+	require.NotNil(t, err)
+	t.Log(err)
+
+	require.NotNil(t, tableUnderTest.SetRawFieldValue(row, 1, []byte{0x01}))
+	require.Equal(t, tableUnderTest.dataStore, data)
+}
+
+func TestDbfTable_DBaseBinaryMemoField_KeptAsRawField(t *testing.T) {
+	table := New(nil)
+	require.Nil(t, table.AddMemoField("BINARY"))
+	require.Nil(t, table.AddTextField("AFTER", 5))
+
+	// dBase 'B' fields hold 10-byte memo block pointers, rather than Visual FoxPro doubles.
+	table.dataStore[32+11] = 'B'
+
+	tableUnderTest, err := NewFromByteArray(table.dataStore, nil)
+	require.Nil(t, err)
+	require.True(t, tableUnderTest.Fields()[0].IsRaw())
+	require.EqualValues(t, tableUnderTest.fieldOffset(1), 11)
+}
+
+func TestDbfTable_RawFieldValue_KnownType(t *testing.T) {
+	tableUnderTest, err := NewFromFile(validTestFile, nil)
+	require.Nil(t, err)
+
+	require.Equal(t, tableUnderTest.RawFieldValue(0, 1), []byte("test0     "))
+
+	_, err = tableUnderTest.RawFieldValueByName(0, "missingField")
+	require.NotNil(t, err)
+}
+
+// requireTestTable fails the test unless all the errors of building the table, such as those of adding its fields, are
+// nil, then adds the given number of blank records to the table.
+func requireTestTable(t *testing.T, table *DbfTable, numberOfRecords int, errs ...error) *DbfTable {
+	for _, err := range errs {
+		require.Nil(t, err)
+	}
+	for i := 0; i < numberOfRecords; i++ {
+		_, err := table.AddNewRecord()
+		require.Nil(t, err)
+	}
+	return table
+}
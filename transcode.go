@@ -0,0 +1,243 @@
+package godbf
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+)
+
+// UnrepresentableValue describes a value that Transcode() could not represent in the target encoding, either because
+// some of its characters do not exist in the target code page, or because it no longer fits its field once encoded.
+type UnrepresentableValue struct {
+	Row       int    // row of the value, or -1 for a field name
+	Field     string // name of the field, as it was before transcoding
+	Value     string // the value, as decoded from the table's encoding
+	Truncated bool   // true if the value is too long for its field once encoded
+}
+
+// TranscodeOption configures how Transcode() handles values that cannot be represented in the target encoding.
+type TranscodeOption func(t *transcoder)
+
+// ReplaceUnrepresentable has Transcode() replace characters that do not exist in the target code page with the
+// replacement given, and cut values that no longer fit their field, rather than fail. The replacement must exist in
+// the target code page.
+func ReplaceUnrepresentable(replacement rune) TranscodeOption {
+	return func(t *transcoder) {
+		t.replace = true
+		t.replacement = replacement
+	}
+}
+
+// transcoder re-encodes values decoded from a table's encoding into a target encoding.
+type transcoder struct {
+	dst             encoding.Encoding
+	replace         bool
+	replacement     rune
+	unrepresentable []UnrepresentableValue
+}
+
+// transcodedMemo is a text memo awaiting to be written to the memo file in the target encoding.
+type transcodedMemo struct {
+	row        int
+	fieldIndex int
+	value      []byte
+}
+
+// Transcode converts the table to the encoding given, rewriting field names, Character fields and text memos, and
+// setting the language driver ID of the header to match. Fields holding binary data are left unchanged.
+//
+// Values that cannot be represented in the target encoding are returned. Unless configured otherwise with
+// ReplaceUnrepresentable(), an error is returned when there are any, and the table is left unchanged.
+func (dt *DbfTable) Transcode(dst encoding.Encoding, options ...TranscodeOption) (unrepresentable []UnrepresentableValue, err error) {
+	t := &transcoder{dst: dst, replacement: '?'}
+	for _, option := range options {
+		option(t)
+	}
+
+	if t.replace {
+		if _, ok := t.encodeRune(t.replacement); !ok {
+			return nil, fmt.Errorf("replacement '%c' cannot be represented in %v", t.replacement, dst)
+		}
+	}
+
+	var names []string
+	if names, err = dt.transcodeFieldNames(t); err != nil {
+		return
+	}
+
+	records := make([]byte, len(dt.dataStore)-int(dt.numberOfBytesInHeader))
+	copy(records, dt.dataStore[dt.numberOfBytesInHeader:])
+
+	var memos []transcodedMemo
+	if memos, err = dt.transcodeRecords(t, records); err != nil {
+		return
+	}
+
+	unrepresentable = t.unrepresentable
+	if len(unrepresentable) > 0 && !t.replace {
+		first := unrepresentable[0]
+		err = fmt.Errorf("%d values cannot be represented in %v, such as \"%s\" of field \"%s\"",
+			len(unrepresentable), dst, first.Value, first.Field)
+		return
+	}
+
+	err = dt.applyTranscoding(dst, names, records, memos)
+	return
+}
+
+// transcodeFieldNames returns the names of the fields, as they will be once represented in the target encoding.
+func (dt *DbfTable) transcodeFieldNames(t *transcoder) (names []string, err error) {
+	maxLength := dt.format.fieldDescriptorLayout().maxUsableNameByteLength()
+	names = make([]string, len(dt.fields))
+	seen := make(map[string]bool, len(dt.fields))
+
+	for i := range dt.fields {
+		names[i], _ = t.transcode(-1, dt.fields[i].name, dt.fields[i].name, maxLength)
+		if seen[names[i]] {
+			return nil, fmt.Errorf("Field name \"%s\" already exists", names[i])
+		}
+		seen[names[i]] = true
+	}
+	return
+}
+
+// transcodeRecords rewrites the Character fields of the records given, and returns the text memos to be rewritten.
+func (dt *DbfTable) transcodeRecords(t *transcoder, records []byte) (memos []transcodedMemo, err error) {
+	for row := 0; row < dt.NumberOfRecords(); row++ {
+		record := records[row*int(dt.lengthOfEachRecord) : (row+1)*int(dt.lengthOfEachRecord)]
+
+		for i := range dt.fields {
+			fd := &dt.fields[i]
+			if fd.raw || fd.holdsBinaryData() {
+				continue
+			}
+
+			switch {
+			case fd.fieldType == Character:
+				offset := dt.fieldOffset(i)
+				field := record[offset : offset+int(fd.length)]
+
+				var value string
+				if value, err = dt.characterValue(field); err != nil {
+					return
+				}
+
+				_, encoded := t.transcode(row, fd.name, value, len(field))
+				fillFieldWithBlanks(field)
+				copy(field, encoded)
+			case fd.fieldType.usesMemo():
+				var value string
+				if value, err = dt.memoValueFromRecord(record, i); err != nil {
+					return
+				}
+				if value == "" {
+					continue
+				}
+
+				_, encoded := t.transcode(row, fd.name, value, -1)
+				memos = append(memos, transcodedMemo{row: row, fieldIndex: i, value: encoded})
+			}
+		}
+	}
+	return
+}
+
+// characterValue decodes the content of a Character field, without its trailing blanks.
+func (dt *DbfTable) characterValue(field []byte) (string, error) {
+	temp := make([]byte, len(field))
+	copy(temp, field)
+	enforceBlankPadding(temp)
+
+	decoded, err := dt.decodeBytes(temp)
+	return strings.TrimRight(string(decoded), " "), err
+}
+
+// applyTranscoding replaces the field names, records and text memos of the table with their transcoded versions, and
+// switches the table over to the target encoding.
+func (dt *DbfTable) applyTranscoding(dst encoding.Encoding, names []string, records []byte, memos []transcodedMemo) (err error) {
+	layout := dt.format.fieldDescriptorLayout()
+	fieldMap := make(map[string]int, len(names))
+
+	for i := range dt.fields {
+		nameBytes, _ := encodeWith(dst, names[i])
+		descriptor := dt.fields[i].fieldStore
+		clearBytes(descriptor[:layout.nameLength])
+		copy(descriptor, nameBytes)
+
+		headerOffset := dt.format.headerPrefixLength() + i*layout.descriptorLength
+		copy(dt.dataStore[headerOffset:headerOffset+layout.nameLength], descriptor[:layout.nameLength])
+
+		dt.fields[i].name = names[i]
+		fieldMap[names[i]] = i
+	}
+	dt.fieldMap = fieldMap
+
+	copy(dt.dataStore[dt.numberOfBytesInHeader:], records)
+	for _, memo := range memos {
+		// the rewritten memos reuse the blocks of the originals where they fit, the memo file growing otherwise
+		if err = dt.setMemoBytesInRecord(dt.record(memo.row), memo.fieldIndex, memo.value, false); err != nil {
+			return
+		}
+	}
+
+	dt.useEncoding(dst)
+	dt.dataStore[languageDriverIDIndex] = codePageID(dst)
+	if dt.format == DBase7 {
+		// dBase 7 reads the code page from the language driver name rather than the ID, so the name of the source
+		// code page is cleared, as for tables created by New()
+		err = dt.SetLanguageDriverName("")
+	}
+	return
+}
+
+// transcode represents the value in the target encoding, recording it as unrepresentable if any of its characters do
+// not exist in the target code page, or if it is longer than maxLength bytes once encoded. A negative maxLength means
+// there is no limit. With replacement enabled, the characters are replaced and the value cut to fit, so that both the
+// value as it is now stored, and its encoding are returned.
+func (t *transcoder) transcode(row int, field string, value string, maxLength int) (stored string, encoded []byte) {
+	if b, ok := encodeWith(t.dst, value); ok && (maxLength < 0 || len(b) <= maxLength) {
+		return value, b
+	}
+
+	representable := make([]rune, 0, len(value))
+	for _, r := range value {
+		if _, ok := t.encodeRune(r); !ok {
+			r = t.replacement
+		}
+		representable = append(representable, r)
+	}
+	stored = string(representable)
+	encoded, _ = encodeWith(t.dst, stored)
+
+	truncated := false
+	for maxLength >= 0 && len(encoded) > maxLength {
+		truncated = true
+		_, size := utf8.DecodeLastRuneInString(stored)
+		stored = stored[:len(stored)-size]
+		encoded, _ = encodeWith(t.dst, stored)
+	}
+
+	t.unrepresentable = append(t.unrepresentable, UnrepresentableValue{
+		Row:       row,
+		Field:     field,
+		Value:     value,
+		Truncated: truncated,
+	})
+	return
+}
+
+func (t *transcoder) encodeRune(r rune) ([]byte, bool) {
+	return encodeWith(t.dst, string(r))
+}
+
+// encodeWith encodes the value with the encoding given, returning false if it cannot be represented. A nil encoding
+// passes the value on as it is.
+func encodeWith(enc encoding.Encoding, value string) ([]byte, bool) {
+	if enc == nil {
+		return []byte(value), true
+	}
+	b, err := enc.NewEncoder().Bytes([]byte(value))
+	return b, err == nil
+}
@@ -0,0 +1,122 @@
+package godbf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func newTranscodingTestTable(t *testing.T, values ...string) *DbfTable {
+	table := New(charmap.CodePage866)
+	requireTestTable(t, table, 0,
+		table.AddTextField("ИМЯ", 8), table.AddNumberField("N", 3, 0), table.AddMemoField("ЗАМЕТКА"))
+	for _, value := range values {
+		row, err := table.AddNewRecord()
+		require.Nil(t, err)
+		require.Nil(t, table.SetFieldValue(row, 0, value))
+		require.Nil(t, table.SetFieldValue(row, 1, "7"))
+		require.Nil(t, table.SetFieldValue(row, 2, value+" заметка"))
+	}
+	return table
+}
+
+func TestDbfTable_Transcode_RoundTrip(t *testing.T) {
+	table := newTranscodingTestTable(t, "Привет", "Мир")
+	original := table.RawFieldValue(0, 0)
+
+	unrepresentable, err := table.Transcode(charmap.Windows1251)
+	require.Nil(t, err)
+	require.Empty(t, unrepresentable)
+	require.EqualValues(t, 0xC9, table.LanguageDriverID())
+	require.Equal(t, charmap.Windows1251, table.Encoding())
+	require.NotEqual(t, original, table.RawFieldValue(0, 0))
+
+	loaded, err := NewFromByteArrayWithMemo(table.dataStore, table.memo.bytes(), Auto(nil))
+	require.Nil(t, err)
+	require.Equal(t, []string{"ИМЯ", "N", "ЗАМЕТКА"}, loaded.FieldNames())
+	for row, value := range []string{"Привет", "Мир"} {
+		v, err := loaded.FieldValueByName(row, "ИМЯ")
+		require.Nil(t, err)
+		require.Equal(t, value, v)
+		v, err = loaded.FieldValueByName(row, "N")
+		require.Nil(t, err)
+		require.Equal(t, "7", v)
+		v, err = loaded.FieldValueByName(row, "ЗАМЕТКА")
+		require.Nil(t, err)
+		require.Equal(t, value+" заметка", v)
+	}
+
+	_, err = loaded.Transcode(charmap.CodePage866)
+	require.Nil(t, err)
+	require.Equal(t, original, loaded.RawFieldValue(0, 0))
+	require.EqualValues(t, 0x65, loaded.LanguageDriverID())
+}
+
+func TestDbfTable_Transcode_Unrepresentable_FailsLeavingTableUnchanged(t *testing.T) {
+	table := newTranscodingTestTable(t, "Привет", "Мир")
+	before := append([]byte(nil), table.dataStore...)
+
+	unrepresentable, err := table.Transcode(charmap.Windows1252)
+	require.NotNil(t, err)
+	require.Equal(t, before, table.dataStore)
+	require.Equal(t, charmap.CodePage866, table.Encoding())
+
+	// two field names, then a value and a memo for each row
+	require.Len(t, unrepresentable, 6)
+	require.Equal(t, UnrepresentableValue{Row: -1, Field: "ИМЯ", Value: "ИМЯ"}, unrepresentable[0])
+	require.Equal(t, UnrepresentableValue{Row: 1, Field: "ИМЯ", Value: "Мир"}, unrepresentable[4])
+}
+
+func TestDbfTable_Transcode_ReplaceUnrepresentable(t *testing.T) {
+	table := New(charmap.CodePage866)
+	require.Nil(t, table.AddTextField("NAME", 6))
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Nil(t, table.SetFieldValue(row, 0, "Ab Цd"))
+
+	unrepresentable, err := table.Transcode(charmap.Windows1252, ReplaceUnrepresentable('?'))
+	require.Nil(t, err)
+	require.Equal(t, []UnrepresentableValue{{Row: 0, Field: "NAME", Value: "Ab Цd"}}, unrepresentable)
+	require.Equal(t, "Ab ?d", table.FieldValue(row, 0))
+
+	_, err = table.Transcode(charmap.Windows1252, ReplaceUnrepresentable('Ц'))
+	require.NotNil(t, err)
+}
+
+func TestDbfTable_Transcode_TruncatesValuesThatNoLongerFit(t *testing.T) {
+	table := New(charmap.CodePage866)
+	require.Nil(t, table.AddTextField("NAME", 4))
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Nil(t, table.SetFieldValue(row, 0, "Мир!"))
+
+	unrepresentable, err := table.Transcode(unicode.UTF8)
+	require.NotNil(t, err)
+	require.Equal(t, []UnrepresentableValue{{Row: 0, Field: "NAME", Value: "Мир!", Truncated: true}}, unrepresentable)
+
+	_, err = table.Transcode(unicode.UTF8, ReplaceUnrepresentable('?'))
+	require.Nil(t, err)
+	require.Equal(t, "Ми", table.FieldValue(row, 0))
+}
+
+func TestDbfTable_Transcode_DBase7_ClearsLanguageDriverName(t *testing.T) {
+	table := New(charmap.CodePage866, WithFormat(DBase7))
+	require.Nil(t, table.AddTextField("NAME", 8))
+	require.Nil(t, table.SetLanguageDriverName("DB866RU0"))
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+	require.Nil(t, table.SetFieldValue(row, 0, "Привет"))
+
+	_, err = table.Transcode(charmap.Windows1251)
+	require.Nil(t, err)
+	require.EqualValues(t, 0xC9, table.LanguageDriverID())
+	require.Equal(t, "", table.LanguageDriverName())
+
+	loaded, err := NewFromByteArray(table.dataStore, Auto(nil))
+	require.Nil(t, err)
+	require.Equal(t, DBase7, loaded.Format())
+	require.Equal(t, "", loaded.LanguageDriverName())
+	require.Equal(t, "Привет", loaded.FieldValue(row, 0))
+}
@@ -0,0 +1,137 @@
+package godbf
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+// TypedFieldValue returns the value for the record at the given row and field index as the Go type that suits the
+// field's type: a string for Character and Memo fields, an int64 for Integer fields and Numeric fields without decimal
+// places, a float64 for Currency, Double and other Numeric and Float fields, a bool for Logical fields, a time.Time for
+// Date, DateTime and Timestamp fields, and a []byte for General and Picture fields and fields of types that are not
+// understood. NULL values, as per FieldIsNull(), are returned as nil.
+func (dt *DbfTable) TypedFieldValue(row int, fieldIndex int) (value interface{}, err error) {
+	fd := &dt.fields[fieldIndex]
+	if dt.FieldIsNull(row, fieldIndex) {
+		return nil, nil
+	}
+
+	switch {
+	case fd.raw:
+		return dt.RawFieldValue(row, fieldIndex), nil
+	case fd.fieldType.isBinaryMemo():
+		return dt.BinaryMemoFieldValue(row, fieldIndex)
+	case fd.fieldType.usesMemo():
+		return dt.MemoFieldValue(row, fieldIndex)
+	}
+
+	switch fd.fieldType {
+	case Numeric, Float:
+		s := dt.FieldValue(row, fieldIndex)
+		if fd.decimalPlaces == 0 {
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return i, nil
+			}
+		}
+		if value, err = strconv.ParseFloat(s, 64); err != nil {
+			err = fmt.Errorf("invalid number \"%s\" in field \"%s\"", s, fd.name)
+		}
+		return
+	case Integer, AutoIncrement:
+		return strconv.ParseInt(dt.FieldValue(row, fieldIndex), 10, 64)
+	case Currency, Double, Double7:
+		return strconv.ParseFloat(dt.FieldValue(row, fieldIndex), 64)
+	case Logical:
+		b, valid, err := dt.BoolFieldValue(row, fieldIndex)
+		if !valid || err != nil {
+			return nil, err
+		}
+		return b, nil
+	case Date:
+		return dt.TimeFieldValue(row, fieldIndex)
+	case DateTime, Timestamp:
+		t, err := dt.DateTimeFieldValueByName(row, fd.name)
+		if t.IsZero() || err != nil {
+			return nil, err
+		}
+		return t, nil
+	}
+	return dt.FieldValue(row, fieldIndex), nil
+}
+
+// SetTypedFieldValue sets the value for the record at the given row and field index from a Go value of one of the
+// types returned by TypedFieldValue(), or an int, a uint64 or a *big.Rat. Values are converted to the field's type
+// where they can be represented without loss; numbers are rounded to the decimal places of Numeric and Float fields.
+// Nil sets the field to NULL, as per SetFieldNull(), or blanks it for fields that cannot hold NULL. An error is
+// returned if the value cannot be converted.
+func (dt *DbfTable) SetTypedFieldValue(row int, fieldIndex int, value interface{}) error {
+	fd := &dt.fields[fieldIndex]
+
+	switch v := value.(type) {
+	case nil:
+		if err := dt.SetFieldNull(row, fieldIndex); err == nil || fd.raw {
+			return err
+		}
+		return dt.SetFieldValue(row, fieldIndex, "")
+	case string:
+		return dt.convertValue(row, fieldIndex, v)
+	case []byte:
+		switch {
+		case fd.raw:
+			return dt.SetRawFieldValue(row, fieldIndex, v)
+		case fd.fieldType.usesMemo():
+			return dt.SetBinaryMemoFieldValue(row, fieldIndex, v)
+		}
+		return dt.convertValue(row, fieldIndex, string(v))
+	case int:
+		return dt.convertValue(row, fieldIndex, strconv.Itoa(v))
+	case int64:
+		return dt.convertValue(row, fieldIndex, strconv.FormatInt(v, 10))
+	case uint64:
+		return dt.convertValue(row, fieldIndex, strconv.FormatUint(v, 10))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("value %v for field \"%s\" is not a number", v, fd.name)
+		}
+		return dt.convertValue(row, fieldIndex, strconv.FormatFloat(v, 'f', -1, 64))
+	case *big.Rat:
+		if v == nil {
+			return dt.SetTypedFieldValue(row, fieldIndex, nil)
+		}
+		if fd.fieldType.oneOf(Numeric, Float) {
+			return dt.setNumericFieldValue(row, fieldIndex, v, nil)
+		}
+		return dt.convertValue(row, fieldIndex, v.FloatString(ratDecimalPlaces(v)))
+	case bool:
+		if fd.fieldType == Logical {
+			return dt.SetBoolFieldValue(row, fieldIndex, v)
+		}
+		return dt.convertValue(row, fieldIndex, strconv.FormatBool(v))
+	case time.Time:
+		switch fd.fieldType {
+		case Date:
+			return dt.SetTimeFieldValue(row, fieldIndex, v)
+		case DateTime, Timestamp:
+			return dt.SetDateTimeFieldValueByName(row, fd.name, v)
+		}
+	}
+	return fmt.Errorf("cannot store %T in field \"%s\" of type '%c'", value, fd.name, fd.fieldType)
+}
+
+// ratDecimalPlaces returns the number of decimal places needed to represent the rational number exactly, up to 20
+// for numbers such as 1/3 that have no exact decimal representation.
+func ratDecimalPlaces(r *big.Rat) int {
+	const maxDecimalPlaces = 20
+	ten := big.NewInt(10)
+	scaled := new(big.Rat).Set(r)
+	for places := 0; places < maxDecimalPlaces; places++ {
+		if scaled.IsInt() {
+			return places
+		}
+		scaled.Mul(scaled, new(big.Rat).SetInt(ten))
+	}
+	return maxDecimalPlaces
+}
@@ -0,0 +1,54 @@
+package godbf
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDbfTable_TypedFieldValue(t *testing.T) {
+	table := New(nil, WithFormat(VisualFoxPro))
+	require.Nil(t, table.AddTextField("NAME", 10))
+	require.Nil(t, table.AddNumberField("COUNT", 5, 0))
+	require.Nil(t, table.AddNumberField("AMOUNT", 8, 2))
+	require.Nil(t, table.AddIntegerField("ID"))
+	require.Nil(t, table.AddCurrencyField("PRICE"))
+	require.Nil(t, table.AddBooleanField("PAID"))
+	require.Nil(t, table.AddDateField("BORN"))
+	require.Nil(t, table.AddDateTimeField("SEEN"))
+	require.Nil(t, table.AddPictureField("PHOTO"))
+	row, err := table.AddNewRecord()
+	require.Nil(t, err)
+
+	seen := time.Date(2024, time.March, 1, 12, 30, 0, 0, time.UTC)
+	values := []interface{}{"name", int64(42), 12.5, int64(-7), 1.2345, true,
+		time.Date(1999, time.December, 31, 0, 0, 0, 0, time.Local), seen, []byte{0x89, 'P', 'N', 'G'}}
+	for i, value := range values {
+		require.Nil(t, table.SetTypedFieldValue(row, i, value))
+	}
+	for i, expected := range values {
+		value, err := table.TypedFieldValue(row, i)
+		require.Nil(t, err)
+		require.Equal(t, value, expected)
+	}
+
+	require.Nil(t, table.SetTypedFieldValue(row, 2, big.NewRat(1, 3)))
+	require.Equal(t, table.FieldValue(row, 2), "0.33")
+	require.Nil(t, table.SetTypedFieldValue(row, 0, 12.5))
+	require.Equal(t, table.FieldValue(row, 0), "12.5")
+
+	for _, fieldIndex := range []int{0, 1, 5, 6, 7} {
+		require.Nil(t, table.SetTypedFieldValue(row, fieldIndex, nil))
+	}
+	require.Equal(t, table.FieldValue(row, 0), "")
+	for _, fieldIndex := range []int{1, 5, 6, 7} {
+		value, err := table.TypedFieldValue(row, fieldIndex)
+		require.Nil(t, err)
+		require.Nil(t, value)
+	}
+
+	require.EqualError(t, table.SetTypedFieldValue(row, 5, seen), "cannot store time.Time in field \"PAID\" of type 'L'")
+	require.EqualError(t, table.SetTypedFieldValue(row, 3, 1.5), "value 1.5 is not a whole number")
+}
@@ -0,0 +1,149 @@
+package godbf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Writer streams the records of a new dBase table straight to an io.WriteSeeker, so that no more than a single record
+// is held in memory. The table schema is defined up front on a DbfTable, using the usual AddTextField(),
+// AddNumberField(), etc. calls:
+//
+//	schema := godbf.New(charmap.CodePage866)
+//	err = schema.AddTextField("NAME", 20)
+//
+//	writer, err := godbf.NewWriter(file, schema)
+//	for _, name := range names {
+//	  _, err = writer.AddNewRecord()
+//	  err = writer.SetFieldValueByName("NAME", name)
+//	}
+//	err = writer.Close()
+type Writer struct {
+	table *DbfTable // schema of the table being written
+	dest  io.WriteSeeker
+	buf   *bufio.Writer
+	start int64 // position of the table header in dest
+
+	record          []byte // record currently being filled, nil if there is none
+	numberOfRecords uint32
+	closed          bool
+}
+
+// NewWriter creates a Writer for a table with the schema of the supplied DbfTable, immediately writing a placeholder
+// header to dest. The schema of the supplied table is locked, and its records (if any) are ignored.
+// Schemas with memo fields are not supported.
+func NewWriter(dest io.WriteSeeker, schema *DbfTable) (writer *Writer, err error) {
+	if schema.lengthOfEachRecord <= 1 {
+		return nil, errors.New("attempted to write a table with no fields defined")
+	}
+	if schema.hasMemoFields() {
+		return nil, errors.New("tables with memo fields cannot be streamed, as their memo file is not written")
+	}
+
+	var start int64
+	if start, err = dest.Seek(0, io.SeekCurrent); err != nil {
+		return
+	}
+
+	lockSchema(schema)
+
+	writer = &Writer{
+		table: schema,
+		dest:  dest,
+		buf:   bufio.NewWriter(dest),
+		start: start,
+	}
+
+	header := make([]byte, schema.numberOfBytesInHeader)
+	copy(header, schema.dataStore)
+	copy(header[4:8], uint32ToBytes(0)) // patched on Close()
+
+	_, err = writer.buf.Write(header)
+	return
+}
+
+// AddNewRecord starts a new empty record, writing out the previous one, and returns the index number of the record.
+func (w *Writer) AddNewRecord() (newRecordNumber int, err error) {
+	if w.closed {
+		return -1, errors.New("writer is already closed")
+	}
+	if err = w.flushRecord(); err != nil {
+		return -1, err
+	}
+
+	// the pending record is not counted until it is written out
+	w.record = w.table.emptyRecord()
+	w.table.assignAutoIncrementValues(w.record)
+	return int(w.numberOfRecords), nil
+}
+
+// SetFieldValueByName sets the value of the current record for the field name as specified
+// If the field name does not exist, or the value is incompatible with the field's type, an error is returned.
+func (w *Writer) SetFieldValueByName(fieldName string, value string) error {
+	if fieldIndex, found := w.table.fieldMap[fieldName]; found {
+		return w.SetFieldValue(fieldIndex, value)
+	}
+	return fmt.Errorf("Field name \"%s\" does not exist", fieldName)
+}
+
+// SetFieldValue sets the value of the current record for the given field index as specified
+// If there is no current record, or the value is incompatible with the field's type, an error is returned.
+func (w *Writer) SetFieldValue(fieldIndex int, value string) error {
+	if w.record == nil {
+		return errors.New("no record to set a value on, call AddNewRecord() first")
+	}
+	return w.table.setFieldValueInRecord(w.record, fieldIndex, value)
+}
+
+// Close writes out the current record and the end-of-file marker, then rewrites the header with the number of records.
+// Close does not close the underlying io.WriteSeeker.
+func (w *Writer) Close() (err error) {
+	if w.closed {
+		return errors.New("writer is already closed")
+	}
+	w.closed = true
+
+	if err = w.flushRecord(); err != nil {
+		return
+	}
+	if err = w.buf.WriteByte(endOfFileMarker); err != nil {
+		return
+	}
+	if err = w.buf.Flush(); err != nil {
+		return
+	}
+
+	var end int64
+	if end, err = w.dest.Seek(0, io.SeekCurrent); err != nil {
+		return
+	}
+	// rewrite the whole header, as assigning autoincrement values advances the header too
+	header := make([]byte, w.table.numberOfBytesInHeader)
+	copy(header, w.table.dataStore)
+	copy(header[4:8], uint32ToBytes(w.numberOfRecords))
+
+	if _, err = w.dest.Seek(w.start, io.SeekStart); err != nil {
+		return
+	}
+	if _, err = w.dest.Write(header); err != nil {
+		return
+	}
+	_, err = w.dest.Seek(end, io.SeekStart)
+	return
+}
+
+// flushRecord writes out the record currently being filled, if there is one.
+func (w *Writer) flushRecord() error {
+	if w.record == nil {
+		return nil
+	}
+
+	if _, err := w.buf.Write(w.record); err != nil {
+		return err
+	}
+	w.record = nil
+	w.numberOfRecords++
+	return nil
+}
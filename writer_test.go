@@ -0,0 +1,89 @@
+package godbf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_LoadOfWrittenIsCorrect(t *testing.T) {
+	schema := New(nil)
+	require.Nil(t, schema.AddBooleanField("TESTBOOL"))
+	require.Nil(t, schema.AddTextField("TESTTEXT", 10))
+	require.Nil(t, schema.AddDateField("TESTDATE"))
+	require.Nil(t, schema.AddNumberField("TESTNUM", 10, 0))
+	require.Nil(t, schema.AddFloatField("TESTFLOAT", 10, 2))
+
+	tempFilename := filepath.Join("testdata", "tempWrittenTable.dbf")
+	file, err := os.Create(tempFilename)
+	require.Nil(t, err)
+	defer os.Remove(tempFilename)
+
+	writer, err := NewWriter(file, schema)
+	require.Nil(t, err)
+
+	expectedRows := [][]string{
+		{"T", "test0", "20180101", "42", "42.01"},
+		{"F", "test1", "20180102", "43", "43.02"},
+		{"T", "test2", "20180103", "44", "44.03"},
+	}
+	for i, values := range expectedRows {
+		row, err := writer.AddNewRecord()
+		require.Nil(t, err)
+		require.Equal(t, row, i)
+
+		for fieldIndex, value := range values {
+			require.Nil(t, writer.SetFieldValue(fieldIndex, value))
+		}
+	}
+	require.Nil(t, writer.SetFieldValueByName("TESTTEXT", "changed"))
+	require.Nil(t, writer.Close())
+	require.Nil(t, file.Close())
+	expectedRows[2][1] = "changed"
+
+	tableUnderTest, err := NewFromFile(tempFilename, nil)
+	require.Nil(t, err)
+	require.Equal(t, tableUnderTest.NumberOfRecords(), len(expectedRows))
+	for i, values := range expectedRows {
+		require.Equal(t, tableUnderTest.GetRowAsSlice(i), values)
+	}
+}
+
+func TestWriter_NoFieldsDefined_Errors(t *testing.T) {
+	file, err := os.CreateTemp("", "godbf")
+	require.Nil(t, err)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	_, err = NewWriter(file, New(nil))
+	require.NotNil(t, err)
+	t.Log(err)
+}
+
+func TestWriter_SchemaIsLocked(t *testing.T) {
+	file, err := os.CreateTemp("", "godbf")
+	require.Nil(t, err)
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	schema := New(nil)
+	require.Nil(t, schema.AddTextField("NAME", 10))
+
+	writer, err := NewWriter(file, schema)
+	require.Nil(t, err)
+
+	err = schema.AddTextField("OTHER", 10)
+	require.NotNil(t, err)
+
+	err = writer.SetFieldValueByName("NAME", "no record")
+	require.NotNil(t, err)
+	t.Log(err)
+
+	require.Nil(t, writer.Close())
+	require.NotNil(t, writer.Close())
+
+	_, err = writer.AddNewRecord()
+	require.NotNil(t, err)
+}